@@ -1,23 +1,66 @@
 package validator
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 )
 
 const tagName = "validate"
 const omitemptyFlag = "blank"
+const skipFlag = "-"
+const diveFlag = "dive"
+const keysFlag = "keys"
+const endkeysFlag = "endkeys"
 
 var defaultFeedbackHandlers = map[string]FeedbackHandler{}
 
+// TagNameFunc returns the name of a struct field shown in paths and feedbacks,
+// an empty name keeps the Go name of the field
+type TagNameFunc func(field reflect.StructField) string
+
+// TagName returns a TagNameFunc naming the fields after the tag key, e.g. TagName("json").
+// Options like ",omitempty" are ignored, fields tagged "-" or without the tag keep their Go name
+func TagName(key string) TagNameFunc {
+	return func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		name = strings.TrimSpace(name)
+		if name == "-" {
+			return ""
+		}
+		return name
+	}
+}
+
+// Engine validates structs by their tags. The tags of each struct type are compiled once
+// and cached, use the Register methods rather than modifying Validators and FeedbackHandlers
+// directly, so the cache is refreshed. An Engine is safe for concurrent use.
 type Engine struct {
 	tagName          string
 	FeedbackHandlers map[string]FeedbackHandler
 	Validators       map[string]Validator
 	lock             sync.RWMutex
+	plans            map[reflect.Type]*structPlan
+	vars             map[varKey]*varPlan
+	structValidators map[reflect.Type]StructValidator
+	tagNameFunc      TagNameFunc
+	limits           limits
+	registered       map[reflect.Type]bool
+	registeredTypes  []reflect.Type
+	expensive        map[string]bool
+	concurrency      int
+	// rules are loaded by LoadRules, by struct type and field name
+	rules map[reflect.Type]map[string]*fieldRules
+}
+
+// limits control when the validation stops early
+type limits struct {
+	failFast      bool
+	fieldFailFast bool
+	maxErrors     int
 }
 
 func New() *Engine {
@@ -25,6 +68,12 @@ func New() *Engine {
 		tagName:          tagName,
 		FeedbackHandlers: make(map[string]FeedbackHandler),
 		Validators:       make(map[string]Validator),
+		plans:            make(map[reflect.Type]*structPlan),
+		vars:             make(map[varKey]*varPlan),
+		structValidators: make(map[reflect.Type]StructValidator),
+		registered:       make(map[reflect.Type]bool),
+		expensive:        make(map[string]bool),
+		rules:            make(map[reflect.Type]map[string]*fieldRules),
 	}
 	for k, v := range defaultFeedbackHandlers {
 		engine.FeedbackHandlers[k] = v
@@ -36,101 +85,345 @@ func New() *Engine {
 }
 
 func (self *Engine) Validate(i interface{}) error {
+	return self.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx validates i like Validate, the validators get ctx from Validation.Context, e.g. to query
+// a database within the deadline of a request. The validation stops between fields once ctx is done
+// and ctx.Err() is returned
+func (self *Engine) ValidateCtx(ctx context.Context, i interface{}) error {
 	structVal := reflect.ValueOf(i)
 	if structVal.Kind() == reflect.Pointer {
 		if structVal.IsNil() {
-			return errors.New("Invalid pointer")
+			return &ConfigError{Err: ErrInvalidValue, s: "Invalid pointer"}
 		}
 		structVal = structVal.Elem()
 	}
 	if structVal.Kind() != reflect.Struct {
-		return errors.New("Only support validate 'Struct' type")
+		return &ConfigError{Err: ErrInvalidValue, s: "Only support validate 'Struct' type"}
 	}
-	structTyp := structVal.Type()
+	self.lock.RLock()
 	structError := &ValidationError{
 		Detail: make([]*FieldError, 0),
+		limits: self.limits,
+		ctx:    ctx,
 	}
-	for i := 0; i < structTyp.NumField(); i++ {
-		fieldTyp := structTyp.Field(i)
-		if !fieldTyp.IsExported() {
-			continue
+	concurrency := self.concurrency
+	self.lock.RUnlock()
+	if concurrency > 0 {
+		results, err := self.runExpensive(ctx, structVal, concurrency)
+		if err != nil {
+			return err
 		}
-		if _, ok := fieldTyp.Tag.Lookup(self.tagName); !ok {
-			continue
+		structError.results = results
+	}
+	if err := self.validateStruct(structVal, "", false, structError); err != nil {
+		return err
+	}
+	if len(structError.Detail) > 0 {
+		return structError
+	}
+	return nil
+}
+
+// validateStruct validates the fields of structVal and descends into nested
+// and embedded structs, prefix is the path of structVal from the top struct
+func (self *Engine) validateStruct(structVal reflect.Value, prefix string, embedded bool, structError *ValidationError) error {
+	plan, err := self.structPlan(structVal.Type())
+	if err != nil {
+		return err
+	}
+	for _, fp := range plan.fields {
+		if err := structError.ctxErr(); err != nil {
+			return err
+		}
+		if structError.stop {
+			structError.Truncated = true
+			return nil
 		}
-		if err := self.validateField(fieldTyp, structVal); err != nil {
-			switch e := err.(type) {
-			case *FieldError:
-				structError.Detail = append(structError.Detail, e)
-			default:
+		field := structVal.Field(fp.index)
+		path := joinPath(prefix, fp.name)
+		if fp.value != nil {
+			if err := self.validateValue(fp.field, field, structVal, fp.value, path, structError); err != nil {
 				return err
 			}
+			continue
+		}
+		nested, ok := nestedStruct(field)
+		if !ok {
+			continue
+		}
+		// fields of embedded structs are reported as if they were declared on the outer struct
+		if fp.flatten {
+			path = prefix
+		}
+		if err := self.validateStruct(nested, path, fp.field.Anonymous, structError); err != nil {
+			return err
 		}
 	}
-	if len(structError.Detail) > 0 {
-		return structError
+	if err := structError.ctxErr(); err != nil {
+		return err
 	}
+	if structError.prefetch != nil {
+		return nil
+	}
+	self.validateStructLevel(plan, structVal, prefix, embedded, structError)
 	return nil
 }
 
-func (self *Engine) validateField(fieldTyp reflect.StructField, structVal reflect.Value) error {
-	tag := fieldTyp.Tag.Get(self.tagName)
-	flags := parseFlags(tag)
-	_, omitEmpty := flags[omitemptyFlag]
-	delete(flags, omitemptyFlag)
-	fieldError := &FieldError{
-		Field:     fieldTyp,
-		Feedbacks: make([]*Feedback, 0),
+// validateValue runs the rules on field, then dives into its elements or descends into it
+// if field holds a struct. field is either a struct field or an element of one.
+func (self *Engine) validateValue(fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value, vp *valuePlan, path string, structError *ValidationError) error {
+	if err := self.validateField(fieldTyp, field, structVal, vp, path, structError); err != nil {
+		return err
 	}
-	for flag, param := range flags {
-		// skip empty value
-		field := structVal.Field(fieldTyp.Index[0])
-		if field.IsZero() && omitEmpty {
-			continue
+	if vp.omitEmpty && field.IsZero() {
+		return nil
+	}
+	if vp.dive {
+		return self.validateDive(fieldTyp, field, structVal, vp, path, structError)
+	}
+	nested, ok := nestedStruct(field)
+	if !ok {
+		return nil
+	}
+	if vp.flatten {
+		path = strings.TrimSuffix(strings.TrimSuffix(path, vp.name), ".")
+	}
+	return self.validateStruct(nested, path, fieldTyp.Anonymous, structError)
+}
+
+// validateDive applies the element plan to every element of a slice, array or map,
+// and the keys plan to the keys of a map
+func (self *Engine) validateDive(fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value, vp *valuePlan, path string, structError *ValidationError) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		if vp.keys != nil {
+			return &ConfigError{Field: path, Flag: keysFlag, Err: ErrUnsupportedType, s: fmt.Sprintf("Field '%s': '%s' only support map type", path, keysFlag)}
+		}
+		for i := 0; i < field.Len(); i++ {
+			if err := structError.ctxErr(); err != nil {
+				return err
+			}
+			if structError.stop {
+				structError.Truncated = true
+				return nil
+			}
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if err := self.validateValue(fieldTyp, elemValue(field.Index(i)), structVal, vp.elem, elemPath, structError); err != nil {
+				return err
+			}
 		}
-		v := &Validation{
-			StructField: fieldTyp,
-			Field:       field,
-			Struct:      structVal,
-			Flag:        flag,
-			Param:       param,
-		}
-		if validator, ok := self.Validators[flag]; ok {
-			if err := validator(v); err != nil {
-				switch feedback := err.(type) {
-				case *Feedback:
-					if handler, ok := self.FeedbackHandlers[v.Flag]; ok {
-						feedback.s = handler(feedback)
-					}
-					fieldError.Feedbacks = append(fieldError.Feedbacks, feedback)
-				default:
+	case reflect.Map:
+		for _, key := range sortedMapKeys(field) {
+			if err := structError.ctxErr(); err != nil {
+				return err
+			}
+			if structError.stop {
+				structError.Truncated = true
+				return nil
+			}
+			elemPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+			if vp.keys != nil {
+				if err := self.validateValue(fieldTyp, elemValue(key), structVal, vp.keys, elemPath, structError); err != nil {
 					return err
 				}
 			}
-		} else {
-			return fmt.Errorf("Unregistered validator '%s'", flag)
+			if err := self.validateValue(fieldTyp, elemValue(field.MapIndex(key)), structVal, vp.elem, elemPath, structError); err != nil {
+				return err
+			}
 		}
+	default:
+		return &ConfigError{Field: path, Flag: diveFlag, Err: ErrUnsupportedType, s: fmt.Sprintf("Field '%s': cannot dive into type '%s'", path, field.Type())}
 	}
-	if len(fieldError.Feedbacks) > 0 {
-		return fieldError
+	return nil
+}
+
+// validateField runs the rules on field in declaration order and adds the
+// feedbacks to structError, until one of the limits is reached
+func (self *Engine) validateField(fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value, vp *valuePlan, path string, structError *ValidationError) error {
+	// skip empty value
+	if field.IsZero() && vp.omitEmpty {
+		return nil
+	}
+	if structError.prefetch != nil {
+		structError.prefetch.add(structError.ctx, vp.rules, path, fieldTyp, field, structVal)
+		return nil
+	}
+	var fieldError *FieldError
+	for i, rule := range vp.rules {
+		if structError.limits.maxErrors > 0 && structError.feedbacks >= structError.limits.maxErrors {
+			structError.stop = true
+			structError.Truncated = true
+			break
+		}
+		feedback, err := self.runRule(structError, rule, path, fieldTyp, field, structVal)
+		if err != nil {
+			return err
+		}
+		if feedback == nil {
+			continue
+		}
+		if fieldError == nil {
+			fieldError = &FieldError{
+				Field:     fieldTyp,
+				Name:      vp.name,
+				Path:      path,
+				Feedbacks: make([]*Feedback, 0, len(vp.rules)),
+			}
+			structError.Detail = append(structError.Detail, fieldError)
+		}
+		fieldError.Feedbacks = append(fieldError.Feedbacks, feedback)
+		structError.feedbacks++
+		if structError.limits.fieldFailFast {
+			if i < len(vp.rules)-1 {
+				structError.Truncated = true
+			}
+			break
+		}
+	}
+	if fieldError != nil && structError.limits.failFast {
+		structError.stop = true
 	}
 	return nil
 }
 
+// runRule runs a rule on field, it returns the feedback if the field fails the rule.
+// An OR group fails if all of its alternatives fail, a negated rule fails if its validator passes
+func (self *Engine) runRule(structError *ValidationError, rule *rule, path string, fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value) (*Feedback, error) {
+	v := newValidation(structError.ctx, rule, fieldTyp, field, structVal)
+	var feedback *Feedback
+	switch {
+	case len(rule.alternatives) > 0:
+		feedbacks := make([]*Feedback, 0, len(rule.alternatives))
+		for _, alternative := range rule.alternatives {
+			f, err := self.runRule(structError, alternative, path, fieldTyp, field, structVal)
+			if err != nil || f == nil {
+				return nil, err
+			}
+			feedbacks = append(feedbacks, f)
+		}
+		messages := make([]string, len(feedbacks))
+		for i, f := range feedbacks {
+			messages[i] = f.s
+		}
+		codes := make([]string, len(feedbacks))
+		for i, f := range feedbacks {
+			codes[i] = f.Code
+		}
+		feedback = &Feedback{
+			Validation:   v,
+			Code:         orFlag,
+			Params:       map[string]any{"alternatives": codes},
+			Value:        interfaceValue(field),
+			s:            strings.Join(messages, " or "),
+			alternatives: feedbacks,
+		}
+	case rule.negate:
+		switch err := structError.call(rule, path, v); err.(type) {
+		case nil:
+			v.Flag = notFlag
+			v.Param = rule.text
+			feedback = v.ErrorCode(notFlag, map[string]any{"rule": rule.text},
+				fmt.Sprintf("field must not match '%s'", rule.text)).(*Feedback)
+		case *Feedback:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	default:
+		err := structError.call(rule, path, v)
+		if err == nil {
+			return nil, nil
+		}
+		var ok bool
+		if feedback, ok = err.(*Feedback); !ok {
+			return nil, err
+		}
+	}
+	if rule.handler != nil {
+		feedback.s = rule.handler(feedback)
+	}
+	return feedback, nil
+}
+
+func newValidation(ctx context.Context, rule *rule, fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value) *Validation {
+	return &Validation{
+		StructField: fieldTyp,
+		Field:       field,
+		Struct:      structVal,
+		Flag:        rule.flag,
+		Param:       rule.param,
+		arg:         rule.arg,
+		nameFunc:    rule.nameFunc,
+		ctx:         ctx,
+	}
+}
+
 func (self *Engine) SetTagName(name string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
 	self.tagName = name
+	self.resetPlans()
+}
+
+// TagName returns the key of the validator tags, see SetTagName
+func (self *Engine) TagName() string {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.tagName
+}
+
+// SetFailFast makes the validation stop at the first field that fails
+func (self *Engine) SetFailFast(failFast bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.limits.failFast = failFast
+}
+
+// SetFieldFailFast makes the validation of a field stop at its first failing validator,
+// the other fields are still validated
+func (self *Engine) SetFieldFailFast(failFast bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.limits.fieldFailFast = failFast
+}
+
+// SetMaxErrors makes the validation stop once n feedbacks are collected, 0 means no limit
+func (self *Engine) SetMaxErrors(n int) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.limits.maxErrors = n
+}
+
+// RegisterTagNameFunc sets the function naming the fields in paths and feedbacks,
+// e.g. RegisterTagNameFunc(TagName("json")). Fields it returns an empty name for keep their Go name
+func (self *Engine) RegisterTagNameFunc(fn TagNameFunc) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.tagNameFunc = fn
+	self.resetPlans()
 }
 
 func (self *Engine) RegisterValidator(flag string, validator Validator) {
 	self.lock.Lock()
 	defer self.lock.Unlock()
 	self.Validators[flag] = validator
+	delete(self.expensive, flag)
+	self.resetPlans()
 }
 
 func (self *Engine) RegisterFeedbackHandler(flag string, handler FeedbackHandler) {
 	self.lock.Lock()
 	defer self.lock.Unlock()
 	self.FeedbackHandlers[flag] = handler
+	self.resetPlans()
 }
 
 type Validation struct {
@@ -139,39 +432,175 @@ type Validation struct {
 	Struct      reflect.Value
 	Flag        string
 	Param       string
+	arg         interface{}
+	nameFunc    TagNameFunc
+	ctx         context.Context
 }
 
+// Context returns the context given to ValidateCtx, context.Background() otherwise
+func (self *Validation) Context() context.Context {
+	if self.ctx == nil {
+		return context.Background()
+	}
+	return self.ctx
+}
+
+// Error returns a feedback coded by the flag of the validator, the param if any is kept in Params["param"]
 func (self *Validation) Error(s string) error {
-	return &Feedback{
-		Validation: self,
-		s:          s,
+	var params map[string]any
+	if self.Param != "" {
+		params = map[string]any{"param": self.Param}
 	}
+	return self.ErrorCode(self.Flag, params, s)
 }
 
 func (self *Validation) Errorf(format string, a ...any) error {
+	return self.Error(fmt.Sprintf(format, a...))
+}
+
+// ErrorCode returns a feedback with a machine readable code and typed params,
+// e.g. v.ErrorCode("len.range", map[string]any{"min": 6, "max": 18}, s)
+func (self *Validation) ErrorCode(code string, params map[string]any, s string) error {
 	return &Feedback{
 		Validation: self,
-		s:          fmt.Sprintf(format, a...),
+		Code:       code,
+		Params:     params,
+		Value:      interfaceValue(self.Field),
+		s:          s,
+	}
+}
+
+// FieldName returns the name of a field of Struct as shown in paths and feedbacks,
+// see Engine.RegisterTagNameFunc
+func (self *Validation) FieldName(name string) string {
+	if self.nameFunc == nil || !self.Struct.IsValid() {
+		return name
+	}
+	if field, ok := self.Struct.Type().FieldByName(name); ok {
+		if s := self.nameFunc(field); s != "" {
+			return s
+		}
 	}
+	return name
 }
 
+// ValidatorError returns an error of the configuration of the validator rather than a feedback,
+// see InvalidParam and UnsupportedType for the common ones
 func (self *Validation) ValidatorError(s string) error {
-	return fmt.Errorf("<Field:%s Validator:%s> %s", self.StructField.Name, self.Flag, s)
+	return self.configError(nil, s)
+}
+
+// InvalidParam returns an error wrapping ErrInvalidParam
+func (self *Validation) InvalidParam(s string) error {
+	return self.configError(ErrInvalidParam, s)
+}
+
+// UnsupportedType returns an error wrapping ErrUnsupportedType
+func (self *Validation) UnsupportedType(s string) error {
+	return self.configError(ErrUnsupportedType, s)
 }
 
-func parseFlags(tag string) map[string]string {
-	result := make(map[string]string)
-	flags := strings.Split(tag, ",")
+func (self *Validation) configError(err error, s string) error {
+	return &ConfigError{
+		Field: self.StructField.Name,
+		Flag:  self.Flag,
+		Err:   err,
+		s:     fmt.Sprintf("<Field:%s Validator:%s> %s", self.StructField.Name, self.Flag, s),
+	}
+}
+
+func hasFlag(flags []Flag, name string) bool {
 	for _, flag := range flags {
-		items := strings.SplitN(flag, ":", 2)
-		k := strings.TrimSpace(items[0])
-		if k == "" {
-			continue
-		} else if len(items) < 2 {
-			result[k] = ""
-		} else {
-			result[k] = strings.TrimSpace(items[1])
+		if flag.Name == name {
+			return true
 		}
 	}
-	return result
+	return false
+}
+
+// nestedStruct returns the struct held by field, dereferencing a pointer if needed.
+// time.Time is treated as a value and not descended into
+func nestedStruct(field reflect.Value) (reflect.Value, bool) {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return field, false
+		}
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Struct || field.CanConvert(timeType) {
+		return field, false
+	}
+	return field, true
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// splitDive splits flags at the first dive flag, elemFlags are applied to the elements
+func splitDive(flags []Flag) (fieldFlags []Flag, elemFlags []Flag, dive bool) {
+	for i, flag := range flags {
+		if flag.Name == diveFlag {
+			return flags[:i], flags[i+1:], true
+		}
+	}
+	return flags, nil, false
+}
+
+// splitKeys splits a leading keys...endkeys section from the element flags of a dive
+func splitKeys(flags []Flag) (keysFlags []Flag, elemFlags []Flag, keys bool, err error) {
+	if len(flags) == 0 || flags[0].Name != keysFlag {
+		return nil, flags, false, nil
+	}
+	for i, flag := range flags {
+		if flag.Name == endkeysFlag {
+			return flags[1:i], flags[i+1:], true, nil
+		}
+	}
+	return nil, nil, false, &ConfigError{Flag: keysFlag, Err: ErrInvalidTag, s: fmt.Sprintf("'%s' without '%s'", keysFlag, endkeysFlag)}
+}
+
+// elemValue unwraps the interface of an element of []any or map[string]any
+func elemValue(elem reflect.Value) reflect.Value {
+	if elem.Kind() == reflect.Interface && !elem.IsNil() {
+		return elem.Elem()
+	}
+	return elem
+}
+
+// interfaceValue returns the value held by field, following pointers
+func interfaceValue(field reflect.Value) any {
+	for field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	if !field.IsValid() || !field.CanInterface() {
+		return nil
+	}
+	return field.Interface()
+}
+
+// sortedMapKeys returns the keys of a map in a stable order, so errors are reported deterministically
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	})
+	return keys
 }
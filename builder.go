@@ -0,0 +1,351 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Value is a param of the comparison validators, times are compared in UTC to the second
+type Value interface {
+	~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64 | time.Time
+}
+
+// RuleSource is the rules of a struct type declared in Go, see Rules and Engine.RegisterRules
+type RuleSource interface {
+	typeRules() (reflect.Type, map[string][]Flag, []string, []error)
+}
+
+// TypeRules declares the rules of the fields of T in Go rather than in tags.
+// The rules of a field replace its tag, as LoadRules does without '+'
+type TypeRules[T any] struct {
+	paths []string
+	flags map[string][]Flag
+	// errs are the selectors that don't return a field, see Select
+	errs []error
+}
+
+// Rules starts the rules of the struct type T:
+//
+//	v.RegisterRules(validator.Rules[User]().
+//		Field("Email", validator.Required(), validator.Email()).
+//		Field("Age", validator.Gte(18)))
+func Rules[T any]() *TypeRules[T] {
+	return &TypeRules[T]{flags: make(map[string][]Flag)}
+}
+
+// Field sets the rules of the field at path, a dotted path of Go field names as in LoadRules.
+// Setting a field again replaces its rules
+func (self *TypeRules[T]) Field(path string, flags ...Flag) *TypeRules[T] {
+	if _, ok := self.flags[path]; !ok {
+		self.paths = append(self.paths, path)
+	}
+	self.flags[path] = append([]Flag(nil), flags...)
+	return self
+}
+
+func (self *TypeRules[T]) typeRules() (reflect.Type, map[string][]Flag, []string, []error) {
+	return reflect.TypeOf((*T)(nil)).Elem(), self.flags, self.paths, self.errs
+}
+
+// RegisterRules attaches the rules built by Rules to their types. The rules run the same validators
+// as the tags, they are checked as by Check and nothing is registered if any is wrong
+func (self *Engine) RegisterRules(sources ...RuleSource) error {
+	loaded := make(map[reflect.Type]map[string]*fieldRules)
+	var types []interface{}
+	var errs []error
+	for _, source := range sources {
+		typ, flags, paths, selectErrs := source.typeRules()
+		errs = append(errs, selectErrs...)
+		typ = derefType(typ)
+		if typ.Kind() != reflect.Struct {
+			errs = append(errs, &ConfigError{Err: ErrInvalidValue, s: fmt.Sprintf("Only support rules of 'Struct' type, got '%v'", typ)})
+			continue
+		}
+		types = append(types, reflect.New(typ).Interface())
+		for _, path := range paths {
+			fieldPath := typ.Name() + "." + path
+			owner, field, err := resolveFieldPath(typ, path)
+			if err != nil {
+				errs = append(errs, &ConfigError{Field: fieldPath, Err: ErrInvalidRules, s: fmt.Sprintf("Rules '%s': %s", fieldPath, err)})
+				continue
+			}
+			if err := checkFlags(flags[path]); err != nil {
+				errs = append(errs, &ConfigError{Field: fieldPath, Err: ErrInvalidTag, s: fmt.Sprintf("Rules '%s': %s", fieldPath, err)})
+				continue
+			}
+			if loaded[owner] == nil {
+				loaded[owner] = make(map[string]*fieldRules)
+			}
+			loaded[owner][field.Name] = &fieldRules{flags: flags[path]}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return self.setRules(loaded, types)
+}
+
+// checkFlags rejects the flags the tag parser can't produce
+func checkFlags(flags []Flag) error {
+	for _, flag := range flags {
+		if flag.Name == "" {
+			return fmt.Errorf("missing validator name")
+		}
+		if flag.Negate && controlFlags[flag.Name] {
+			return fmt.Errorf("'%s' can't be negated", flag.Name)
+		}
+		for _, alternative := range flag.Alternatives {
+			if alternative.Name == "" || controlFlags[alternative.Name] || len(alternative.Alternatives) > 0 {
+				return fmt.Errorf("'%s' can't be used in an OR group", alternative)
+			}
+		}
+	}
+	return nil
+}
+
+// Rule returns the flag of a validator by name, e.g. a custom validator
+func Rule(name string, param string) Flag {
+	return Flag{Name: name, Param: param}
+}
+
+// Not negates a validator, as '!' in the tag
+func Not(flag Flag) Flag {
+	flag.Negate = !flag.Negate
+	return flag
+}
+
+// Or passes if any of the validators passes, as '|' in the tag
+func Or(flags ...Flag) Flag {
+	group := Flag{
+		Name:         orFlag,
+		Alternatives: append([]Flag(nil), flags...),
+	}
+	group.Param = group.String()
+	return group
+}
+
+// Blank skips the other validators when the field is empty
+func Blank() Flag {
+	return Flag{Name: omitemptyFlag}
+}
+
+// Dive applies the following validators to the elements of a slice, array or map
+func Dive() Flag {
+	return Flag{Name: diveFlag}
+}
+
+// Keys and EndKeys enclose the validators of the keys of a map after Dive
+func Keys() Flag {
+	return Flag{Name: keysFlag}
+}
+
+// EndKeys ends the validators of the keys started by Keys
+func EndKeys() Flag {
+	return Flag{Name: endkeysFlag}
+}
+
+// Required requires the field to be non-zero
+func Required() Flag {
+	return Flag{Name: "required"}
+}
+
+// Len requires the length to be n
+func Len(n int) Flag {
+	return Flag{Name: "len", Param: strconv.Itoa(n)}
+}
+
+// LenRange requires the length to be between min and max
+func LenRange(min int, max int) Flag {
+	return Flag{Name: "len", Param: strconv.Itoa(min) + "-" + strconv.Itoa(max)}
+}
+
+// Eq requires the value to equal v
+func Eq[V Value](v V) Flag {
+	return Flag{Name: "eq", Param: formatValue(v)}
+}
+
+// Gt requires the value to be greater than v
+func Gt[V Value](v V) Flag {
+	return Flag{Name: "gt", Param: formatValue(v)}
+}
+
+// Gte requires the value to be greater than or equal to v
+func Gte[V Value](v V) Flag {
+	return Flag{Name: "gte", Param: formatValue(v)}
+}
+
+// Lt requires the value to be less than v
+func Lt[V Value](v V) Flag {
+	return Flag{Name: "lt", Param: formatValue(v)}
+}
+
+// Lte requires the value to be less than or equal to v
+func Lte[V Value](v V) Flag {
+	return Flag{Name: "lte", Param: formatValue(v)}
+}
+
+// Phone requires a phone number
+func Phone() Flag {
+	return Flag{Name: "phone"}
+}
+
+// Email requires an email address
+func Email() Flag {
+	return Flag{Name: "email"}
+}
+
+// IP requires an IPv4 or IPv6 address
+func IP() Flag {
+	return Flag{Name: "ip"}
+}
+
+// IPv4 requires an IPv4 address
+func IPv4() Flag {
+	return Flag{Name: "ip", Param: "v4"}
+}
+
+// IPv6 requires an IPv6 address
+func IPv6() Flag {
+	return Flag{Name: "ip", Param: "v6"}
+}
+
+// Number requires an integer or a string of digits
+func Number() Flag {
+	return Flag{Name: "number"}
+}
+
+// Lower requires a string without uppercase letters
+func Lower() Flag {
+	return Flag{Name: "lower"}
+}
+
+// Upper requires a string without lowercase letters
+func Upper() Flag {
+	return Flag{Name: "upper"}
+}
+
+// Alpha requires a string of ASCII letters
+func Alpha() Flag {
+	return Flag{Name: "alpha"}
+}
+
+// Username requires a username, see the username validator
+func Username() Flag {
+	return Flag{Name: "username"}
+}
+
+// Password requires the strength level 1-3, see the password validator
+func Password(level int) Flag {
+	return Flag{Name: "password", Param: strconv.Itoa(level)}
+}
+
+// Prefix requires the string to start with s
+func Prefix(s string) Flag {
+	return Flag{Name: "prefix", Param: s}
+}
+
+// Suffix requires the string to end with s
+func Suffix(s string) Flag {
+	return Flag{Name: "suffix", Param: s}
+}
+
+// EqField requires the value to equal the other field
+func EqField(field string) Flag {
+	return Flag{Name: "eq_field", Param: field}
+}
+
+// LtField requires the value to be less than the other field
+func LtField(field string) Flag {
+	return Flag{Name: "lt_field", Param: field}
+}
+
+// LteField requires the value to be less than or equal to the other field
+func LteField(field string) Flag {
+	return Flag{Name: "lte_field", Param: field}
+}
+
+// GtField requires the value to be greater than the other field
+func GtField(field string) Flag {
+	return Flag{Name: "gt_field", Param: field}
+}
+
+// GteField requires the value to be greater than or equal to the other field
+func GteField(field string) Flag {
+	return Flag{Name: "gte_field", Param: field}
+}
+
+// RequiredIf requires the field when the other field equals value
+func RequiredIf[V Value](field string, value V) Flag {
+	return Flag{Name: "required_if", Param: field + " " + quoteParam(formatValue(value))}
+}
+
+// RequiredUnless requires the field unless the other field equals value
+func RequiredUnless[V Value](field string, value V) Flag {
+	return Flag{Name: "required_unless", Param: field + " " + quoteParam(formatValue(value))}
+}
+
+// RequiredWith requires the field when any of the other fields is present
+func RequiredWith(fields ...string) Flag {
+	return Flag{Name: "required_with", Param: strings.Join(fields, " ")}
+}
+
+// RequiredWithout requires the field when any of the other fields is absent
+func RequiredWithout(fields ...string) Flag {
+	return Flag{Name: "required_without", Param: strings.Join(fields, " ")}
+}
+
+// ExcludedIf requires the field to be empty when the other field equals value
+func ExcludedIf[V Value](field string, value V) Flag {
+	return Flag{Name: "excluded_if", Param: field + " " + quoteParam(formatValue(value))}
+}
+
+// ExcludedUnless requires the field to be empty unless the other field equals value
+func ExcludedUnless[V Value](field string, value V) Flag {
+	return Flag{Name: "excluded_unless", Param: field + " " + quoteParam(formatValue(value))}
+}
+
+// ExcludedWith requires the field to be empty when any of the other fields is present
+func ExcludedWith(fields ...string) Flag {
+	return Flag{Name: "excluded_with", Param: strings.Join(fields, " ")}
+}
+
+// ExcludedWithout requires the field to be empty when any of the other fields is absent
+func ExcludedWithout(fields ...string) Flag {
+	return Flag{Name: "excluded_without", Param: strings.Join(fields, " ")}
+}
+
+// quoteParam quotes a value of the conditional validators that SplitParam wouldn't split back
+func quoteParam(s string) string {
+	if s == "" || s[0] == '"' || strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// formatValue formats a param as it's written in the tag
+func formatValue(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		// the params are parsed as UTC
+		return t.UTC().Format("2006-01-02 15:04:05")
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,371 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// typeChecks report whether the builtin validators support a type, the validators not listed support any type
+var typeChecks = map[string]func(typ reflect.Type) bool{
+	"len":       isLenType,
+	"eq":        isOrderedType,
+	"gt":        isOrderedType,
+	"gte":       isOrderedType,
+	"lt":        isOrderedType,
+	"lte":       isOrderedType,
+	"phone":     isStringType,
+	"email":     isStringType,
+	"ip":        isStringType,
+	"number":    isNumberType,
+	"lower":     isStringType,
+	"upper":     isStringType,
+	"alpha":     isStringType,
+	"username":  isStringType,
+	"password":  isStringType,
+	"eq_field":  isOrderedType,
+	"lt_field":  isOrderedType,
+	"lte_field": isOrderedType,
+	"gt_field":  isOrderedType,
+	"gte_field": isOrderedType,
+	"prefix":    isStringType,
+	"suffix":    isStringType,
+}
+
+// FieldParam tells how the param of a builtin validator names the sibling fields
+type FieldParam struct {
+	// Compare is set for the *_field validators, which compare the field with the named one
+	Compare bool
+	// Pairs is set if every name is followed by a value, as in "Type business"
+	Pairs bool
+}
+
+// fieldParams are the builtin validators whose params name the sibling fields
+var fieldParams = map[string]FieldParam{
+	"eq_field":         {Compare: true},
+	"lt_field":         {Compare: true},
+	"lte_field":        {Compare: true},
+	"gt_field":         {Compare: true},
+	"gte_field":        {Compare: true},
+	"required_if":      {Pairs: true},
+	"required_unless":  {Pairs: true},
+	"required_with":    {},
+	"required_without": {},
+	"excluded_if":      {Pairs: true},
+	"excluded_unless":  {Pairs: true},
+	"excluded_with":    {},
+	"excluded_without": {},
+}
+
+// Check walks the struct types ahead of validation and reports every problem of their tags:
+// invalid tags, unregistered validators, params that can't be parsed, *_field and conditional
+// validators naming missing fields, and validators applied to types they don't support.
+// The nested structs are checked as well. types are values or pointers of the types.
+// The problems are *ConfigError joined by errors.Join, nil if there is none
+func (self *Engine) Check(types ...interface{}) error {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return self.check(self.rules, types)
+}
+
+// check checks the types with the loaded rules, the caller must hold the lock
+func (self *Engine) check(rules map[reflect.Type]map[string]*fieldRules, types []interface{}) error {
+	c := &checker{
+		engine: self,
+		rules:  rules,
+		seen:   make(map[reflect.Type]bool),
+	}
+	for _, i := range types {
+		typ := reflect.TypeOf(i)
+		if typ != nil && typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			c.errs = append(c.errs, &ConfigError{Err: ErrInvalidValue, s: fmt.Sprintf("Only support check 'Struct' type, got '%v'", typ)})
+			continue
+		}
+		c.checkStruct(typ.Name(), typ)
+	}
+	return errors.Join(c.errs...)
+}
+
+// MustRegisterType checks the types and compiles their plans ahead of validation,
+// it panics if Check reports any problem
+func (self *Engine) MustRegisterType(types ...interface{}) {
+	if err := self.Check(types...); err != nil {
+		panic(err)
+	}
+	for _, i := range types {
+		typ := reflect.TypeOf(i)
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		self.structPlan(typ)
+		self.lock.Lock()
+		if !self.registered[typ] {
+			self.registered[typ] = true
+			self.registeredTypes = append(self.registeredTypes, typ)
+		}
+		self.lock.Unlock()
+	}
+}
+
+// RegisteredTypes returns the struct types registered by MustRegisterType, in the order of registration,
+// e.g. to export their schemas
+func (self *Engine) RegisteredTypes() []reflect.Type {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return append([]reflect.Type(nil), self.registeredTypes...)
+}
+
+// checker collects the problems of the types, it walks the tags as compileStruct does
+// but goes on after a problem
+type checker struct {
+	engine *Engine
+	// rules are the loaded rules the types are checked with, see setRules
+	rules map[reflect.Type]map[string]*fieldRules
+	seen  map[reflect.Type]bool
+	errs  []error
+}
+
+func (self *checker) checkStruct(path string, typ reflect.Type) {
+	if self.seen[typ] {
+		return
+	}
+	self.seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		fieldTyp := typ.Field(i)
+		if !fieldTyp.IsExported() && !fieldTyp.Anonymous {
+			continue
+		}
+		flags, ok, skip, err := self.engine.fieldFlags(self.rules, typ, fieldTyp)
+		if skip {
+			continue
+		}
+		fieldPath := path + "." + fieldTyp.Name
+		if err != nil {
+			self.report(fieldPath, "", ErrInvalidTag, err.Error())
+			continue
+		}
+		if !ok {
+			if isStructType(fieldTyp.Type) {
+				self.checkStruct(fieldPath, derefType(fieldTyp.Type))
+			}
+			continue
+		}
+		self.checkValue(fieldPath, fieldTyp.Type, typ, flags)
+	}
+}
+
+// checkValue checks the flags applied to a value of typ, typ is nil when the type is only known at validation time
+func (self *checker) checkValue(path string, typ reflect.Type, structTyp reflect.Type, flags []Flag) {
+	fieldFlags, elemFlags, dive := splitDive(flags)
+	if typ != nil {
+		typ = derefType(typ)
+	}
+	// the rules of an interface field are checked against its dynamic type at validation time
+	ruleTyp := elemType(typ)
+	for _, flag := range fieldFlags {
+		if flag.Name != omitemptyFlag {
+			self.checkRule(path, flag, ruleTyp, structTyp)
+		}
+	}
+	if !dive {
+		if typ != nil && isStructType(typ) {
+			self.checkStruct(path, typ)
+		}
+		return
+	}
+	keysFlags, elemFlags, keys, err := splitKeys(elemFlags)
+	if err != nil {
+		self.report(path, keysFlag, ErrInvalidTag, err.Error())
+		return
+	}
+	var keyTyp, elemTyp reflect.Type
+	if typ != nil {
+		switch typ.Kind() {
+		case reflect.Map:
+			keyTyp = typ.Key()
+			elemTyp = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			if keys {
+				self.report(path, keysFlag, ErrUnsupportedType, fmt.Sprintf("'%s' only support map type", keysFlag))
+				return
+			}
+			elemTyp = typ.Elem()
+		case reflect.Interface:
+		default:
+			self.report(path, diveFlag, ErrUnsupportedType, fmt.Sprintf("cannot dive into type '%s'", typ))
+			return
+		}
+	}
+	if keys {
+		self.checkValue(path+"[key]", elemType(keyTyp), structTyp, keysFlags)
+	}
+	self.checkValue(path+"[*]", elemType(elemTyp), structTyp, elemFlags)
+}
+
+func (self *checker) checkRule(path string, flag Flag, typ reflect.Type, structTyp reflect.Type) {
+	for _, alternative := range flag.Alternatives {
+		self.checkRule(path, alternative, typ, structTyp)
+	}
+	if len(flag.Alternatives) > 0 {
+		return
+	}
+	validator, ok := self.engine.Validators[flag.Name]
+	if !ok {
+		self.report(path, flag.Name, ErrUnregisteredValidator, fmt.Sprintf("Unregistered validator '%s'", flag.Name))
+		return
+	}
+	// the params and types of the validators registered over the builtin ones are unknown
+	if !isBuiltin(flag.Name, validator) {
+		return
+	}
+	if err := checkBuiltin(flag, typ, structTyp); err != nil {
+		self.report(path, flag.Name, err.Err, err.s)
+	}
+}
+
+// checkBuiltin checks the param of a builtin validator and the type it's applied to, typ is nil if unknown.
+// The fields named by the param are only checked if structTyp is not nil
+func checkBuiltin(flag Flag, typ reflect.Type, structTyp reflect.Type) *ConfigError {
+	var arg interface{}
+	if parser, ok := paramParsers[flag.Name]; ok && (structTyp != nil || !isFieldParam(flag.Name)) {
+		var err error
+		if arg, err = parser(flag.Param, typ, structTyp); err != nil {
+			return &ConfigError{Flag: flag.Name, Err: ErrInvalidParam, s: fmt.Sprintf("'%s': %s", flag.String(), err)}
+		}
+	}
+	if typ == nil {
+		return nil
+	}
+	if check, ok := typeChecks[flag.Name]; ok && !check(typ) {
+		return &ConfigError{Flag: flag.Name, Err: ErrUnsupportedType, s: fmt.Sprintf("'%s' not support type '%s'", flag.Name, typ)}
+	}
+	if index, ok := arg.(fieldIndex); ok {
+		target := derefType(structTyp.FieldByIndex(index).Type)
+		if typeClass(target) != typeClass(typ) {
+			return &ConfigError{Flag: flag.Name, Err: ErrUnsupportedType, s: fmt.Sprintf("'%s' cannot compare type '%s' with type '%s'", flag.Name, typ, target)}
+		}
+	}
+	return nil
+}
+
+// CheckFlag checks a flag of the builtin validators applied to a value of typ, for the tools that
+// check the tags without the struct types, e.g. cmd/validatorvet. typ is nil if unknown, a type of
+// the same kind does as well. The fields named by the *_field and conditional validators are not checked.
+// The error is a *ConfigError wrapping ErrUnregisteredValidator, ErrInvalidParam or ErrUnsupportedType
+func CheckFlag(flag Flag, typ reflect.Type) error {
+	for _, alternative := range flag.Alternatives {
+		if err := CheckFlag(alternative, typ); err != nil {
+			return err
+		}
+	}
+	if len(flag.Alternatives) > 0 || controlFlags[flag.Name] {
+		return nil
+	}
+	if _, ok := defaultValidators[flag.Name]; !ok {
+		return &ConfigError{Flag: flag.Name, Err: ErrUnregisteredValidator, s: fmt.Sprintf("Unregistered validator '%s'", flag.Name)}
+	}
+	if typ != nil {
+		typ = derefType(typ)
+	}
+	if err := checkBuiltin(flag, elemType(typ), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FieldParamOf tells how the param of the builtin validator name names the sibling fields, ok is false
+// if it names none. For the tools that check the tags without the struct types, see CheckFlag
+func FieldParamOf(name string) (param FieldParam, ok bool) {
+	param, ok = fieldParams[name]
+	return
+}
+
+// CompareClass groups the types the *_field validators compare alike, a field can be compared with
+// another if their classes are the same and not empty. Pointers are dereferenced
+func CompareClass(typ reflect.Type) string {
+	return typeClass(derefType(typ))
+}
+
+// isBuiltin reports whether validator is the builtin validator of name, not one registered over it
+func isBuiltin(name string, validator Validator) bool {
+	builtin, ok := defaultValidators[name]
+	return ok && reflect.ValueOf(builtin).Pointer() == reflect.ValueOf(validator).Pointer()
+}
+
+func isFieldParam(name string) bool {
+	_, ok := fieldParams[name]
+	return ok
+}
+
+// isCrossField reports whether name is a *_field validator
+func isCrossField(name string) bool {
+	return fieldParams[name].Compare
+}
+
+// isCondition reports whether name is a conditional validator, pairs is set if its param holds field/value pairs
+func isCondition(name string) (pairs bool, ok bool) {
+	param, ok := fieldParams[name]
+	return param.Pairs, ok && !param.Compare
+}
+
+func (self *checker) report(path string, flag string, err error, s string) {
+	self.errs = append(self.errs, &ConfigError{
+		Field: path,
+		Flag:  flag,
+		Err:   err,
+		s:     fmt.Sprintf("Field '%s': %s", path, s),
+	})
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// typeClass groups the types a comparison validator handles alike, empty if it handles none
+func typeClass(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Struct:
+		if typ.ConvertibleTo(timeType) {
+			return "time"
+		}
+	}
+	return ""
+}
+
+func isOrderedType(typ reflect.Type) bool {
+	return typeClass(typ) != ""
+}
+
+func isStringType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.String
+}
+
+func isNumberType(typ reflect.Type) bool {
+	switch typeClass(typ) {
+	case "string", "int", "uint":
+		return true
+	}
+	return false
+}
+
+func isLenType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,829 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/shaopson/validator"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type kind int
+
+const (
+	kindOther kind = iota
+	kindString
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+	kindTime
+	kindStruct
+	kindPointer
+	kindSlice
+	kindArray
+	kindMap
+	kindInterface
+)
+
+// typeInfo is what the generator knows of the type of a value, from the source of the package
+type typeInfo struct {
+	kind kind
+	// expr is the source of the type, e.g. Kind or []string
+	expr string
+	// bits is the size of the float types
+	bits int
+	// name is the name of a struct type of the package, empty for the structs of other packages
+	name      string
+	elem, key *typeInfo
+}
+
+// field is a field of the struct being generated
+type field struct {
+	name string
+	typ  *typeInfo
+}
+
+type generator struct {
+	buf     bytes.Buffer
+	tag     string
+	specs   map[string]*ast.TypeSpec
+	imports map[string]bool
+	// types are the types generated in this run, the nested structs must be among them
+	types map[string]bool
+	// fields of the struct being generated, for the *_field and conditional validators
+	fields map[string]*field
+	// loops counts the loops of the method, for the names of their variables
+	loops int
+	// notNil are the pointers checked by blank in the code being written
+	notNil map[string]bool
+	// info holds the types of the package, for the types of the imported packages
+	info *types.Info
+}
+
+var opFlags = map[string]string{
+	"eq":        "==",
+	"gt":        ">",
+	"gte":       ">=",
+	"lt":        "<",
+	"lte":       "<=",
+	"eq_field":  "==",
+	"gt_field":  ">",
+	"gte_field": ">=",
+	"lt_field":  "<",
+	"lte_field": "<=",
+}
+
+// stringFlags are the validators checked by validator.MatchString
+var stringFlags = map[string]bool{
+	"email":    true,
+	"phone":    true,
+	"ip":       true,
+	"lower":    true,
+	"upper":    true,
+	"alpha":    true,
+	"username": true,
+	"password": true,
+	"prefix":   true,
+	"suffix":   true,
+}
+
+// header marks the generated files, they are skipped when the package is parsed again
+const header = "Code generated by validatorgen; DO NOT EDIT.\n"
+
+// generate returns the source of the Validate methods of the types
+func generate(pkgName string, files []*ast.File, info *types.Info, types []string, tag string) ([]byte, error) {
+	g := &generator{
+		tag:     tag,
+		specs:   make(map[string]*ast.TypeSpec),
+		info:    info,
+		imports: map[string]bool{"github.com/shaopson/validator": true},
+		types:   make(map[string]bool),
+		notNil:  make(map[string]bool),
+	}
+	methods := make(map[string][]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						g.specs[spec.Name.Name] = spec
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil && len(decl.Recv.List) == 1 {
+					recv := decl.Recv.List[0].Type
+					if star, ok := recv.(*ast.StarExpr); ok {
+						recv = star.X
+					}
+					if ident, ok := recv.(*ast.Ident); ok {
+						methods[ident.Name] = append(methods[ident.Name], decl.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	for _, name := range types {
+		spec, ok := g.specs[name]
+		if !ok {
+			return nil, fmt.Errorf("type '%s' not found", name)
+		}
+		if _, ok := spec.Type.(*ast.StructType); !ok {
+			return nil, fmt.Errorf("type '%s' is not a struct", name)
+		}
+		for _, method := range methods[name] {
+			switch method {
+			case "Validate", "ValidatorGenerated":
+				return nil, fmt.Errorf("type '%s' already has a %s method", name, method)
+			case "ValidateWith", "ValidateStruct":
+				return nil, fmt.Errorf("type '%s': struct level validation is not supported", name)
+			}
+		}
+		g.types[name] = true
+	}
+	for _, name := range types {
+		if err := g.genType(name); err != nil {
+			return nil, err
+		}
+	}
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, strconv.Quote(path))
+	}
+	sort.Strings(imports)
+	src := bytes.NewBufferString("// " + header + "\n")
+	fmt.Fprintf(src, "package %s\n\nimport (\n%s\n)\n\n", pkgName, strings.Join(imports, "\n"))
+	src.Write(g.buf.Bytes())
+	return format.Source(src.Bytes())
+}
+
+func (self *generator) printf(format string, a ...interface{}) {
+	fmt.Fprintf(&self.buf, format, a...)
+}
+
+// capture returns the code written by fn instead of writing it
+func (self *generator) capture(fn func() error) (string, error) {
+	buf := self.buf
+	self.buf = bytes.Buffer{}
+	err := fn()
+	code := self.buf.String()
+	self.buf = buf
+	return code, err
+}
+
+func (self *generator) genType(name string) error {
+	st := self.specs[name].Type.(*ast.StructType)
+	self.fields = make(map[string]*field)
+	self.loops = 0
+	type member struct {
+		name     string
+		typ      *typeInfo
+		tag      string
+		tagged   bool
+		embedded bool
+	}
+	var members []member
+	for _, f := range st.Fields.List {
+		typ := self.resolve(f.Type)
+		tag, tagged := "", false
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return err
+			}
+			tag, tagged = reflect.StructTag(s).Lookup(self.tag)
+		}
+		if len(f.Names) == 0 {
+			embedded := typ
+			if embedded.kind == kindPointer {
+				embedded = embedded.elem
+			}
+			fieldName := embedded.name
+			if fieldName == "" {
+				fieldName = embedded.expr[strings.LastIndex(embedded.expr, ".")+1:]
+			}
+			members = append(members, member{fieldName, typ, tag, tagged, true})
+			continue
+		}
+		for _, ident := range f.Names {
+			members = append(members, member{ident.Name, typ, tag, tagged, false})
+		}
+	}
+	for _, m := range members {
+		self.fields[m.name] = &field{name: m.name, typ: m.typ}
+	}
+	body, err := self.capture(func() error {
+		for _, m := range members {
+			exported := ast.IsExported(m.name)
+			if (!exported && !m.embedded) || m.tag == "-" {
+				continue
+			}
+			expr := "self." + m.name
+			path := strconv.Quote(m.name)
+			if !m.tagged || !exported {
+				if err := self.genNested(expr, m.typ, path, m.embedded); err != nil {
+					return fmt.Errorf("%s.%s: %s", name, m.name, err)
+				}
+				continue
+			}
+			flags, err := validator.ParseTag(m.tag)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %s", name, m.name, err)
+			}
+			if err := self.genValue(expr, m.typ, path, m.name, flags, m.embedded); err != nil {
+				return fmt.Errorf("%s.%s: %s", name, m.name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	self.printf("// ValidatorGenerated marks the Validate method of %s as generated, see validator.Generated\n", name)
+	self.printf("func (*%s) ValidatorGenerated() {}\n\n", name)
+	self.printf("// Validate validates the %s tags of %s\n", self.tag, name)
+	self.printf("func (self *%s) Validate() error {\n", name)
+	self.printf("r := validator.NewFailures(self)\n%s", body)
+	self.printf("return r.Err()\n}\n\n")
+	return nil
+}
+
+// genValue writes the checks of the flags applied to the value of expr, path is the expression of its path
+func (self *generator) genValue(expr string, typ *typeInfo, path string, fieldName string, flags []validator.Flag, flatten bool) error {
+	var fieldFlags, elemFlags []validator.Flag
+	dive := false
+	for i, f := range flags {
+		if f.Name == "dive" {
+			fieldFlags, elemFlags, dive = flags[:i], flags[i+1:], true
+			break
+		}
+	}
+	if !dive {
+		fieldFlags = flags
+	}
+	omitEmpty := false
+	for _, f := range fieldFlags {
+		if f.Name == "blank" {
+			omitEmpty = true
+		}
+	}
+	// blank skips the nil pointers, the rules don't check them again
+	if omitEmpty && typ.kind == kindPointer && !self.notNil[expr] {
+		self.notNil[expr] = true
+		defer delete(self.notNil, expr)
+	}
+	body, err := self.capture(func() error {
+		for _, f := range fieldFlags {
+			if f.Name == "blank" {
+				continue
+			}
+			cond, err := self.failCond(f, expr, typ)
+			if err != nil {
+				return err
+			}
+			if cond != "" {
+				self.printf("if %s {\nr.Fail(%s, %q, %s, %q)\n}\n", cond, path, fieldName, expr, ruleText(f))
+			}
+		}
+		if dive {
+			return self.genDive(expr, typ, path, fieldName, elemFlags)
+		}
+		return self.genNested(expr, typ, path, flatten)
+	})
+	if err != nil || body == "" {
+		return err
+	}
+	if !omitEmpty {
+		self.buf.WriteString(body)
+		return nil
+	}
+	zero, err := self.zeroCond(expr, typ)
+	if err != nil {
+		return fmt.Errorf("'blank': %s", err)
+	}
+	self.printf("if %s {\n%s}\n", not(zero), body)
+	return nil
+}
+
+// genNested writes the validation of a nested struct, whose Validate method is generated as well
+func (self *generator) genNested(expr string, typ *typeInfo, path string, flatten bool) error {
+	ptr := typ.kind == kindPointer
+	if ptr {
+		typ = typ.elem
+	}
+	if typ.kind != kindStruct {
+		return nil
+	}
+	if typ.name == "" {
+		return fmt.Errorf("nested type '%s' of another package is not supported by validatorgen", typ.expr)
+	}
+	if !self.types[typ.name] {
+		return fmt.Errorf("nested type '%s' must be generated as well", typ.name)
+	}
+	// an embedded struct keeps the path of the outer struct
+	prefix := path
+	if flatten {
+		prefix = `""`
+	}
+	if ptr && !self.notNil[expr] {
+		self.printf("if %s != nil {\nr.Merge(%s, %s.Validate())\n}\n", expr, prefix, expr)
+	} else {
+		self.printf("r.Merge(%s, %s.Validate())\n", prefix, expr)
+	}
+	return nil
+}
+
+// genDive writes the loop over the elements, and the keys, of the value of expr
+func (self *generator) genDive(expr string, typ *typeInfo, path string, fieldName string, flags []validator.Flag) error {
+	var keysFlags []validator.Flag
+	keys := false
+	if len(flags) > 0 && flags[0].Name == "keys" {
+		for i, f := range flags {
+			if f.Name == "endkeys" {
+				keysFlags, flags, keys = flags[1:i], flags[i+1:], true
+				break
+			}
+		}
+		if !keys {
+			return fmt.Errorf("'keys' without 'endkeys'")
+		}
+	}
+	if typ.kind == kindPointer {
+		if !self.notNil[expr] {
+			self.printf("if %s != nil {\n", expr)
+			defer self.printf("}\n")
+		}
+		expr = "*" + expr
+		typ = typ.elem
+	}
+	n := self.loops
+	self.loops++
+	i, e, k, p := fmt.Sprintf("i%d", n), fmt.Sprintf("e%d", n), fmt.Sprintf("k%d", n), fmt.Sprintf("p%d", n)
+	switch typ.kind {
+	case kindSlice, kindArray:
+		if keys {
+			return fmt.Errorf("'keys' only support map type")
+		}
+		body, err := self.capture(func() error {
+			return self.genValue(e, typ.elem, p, fieldName, flags, false)
+		})
+		if err != nil || body == "" {
+			return err
+		}
+		self.imports["strconv"] = true
+		self.printf("for %s, %s := range %s {\n", i, e, expr)
+		self.printf("%s := %s + strconv.Itoa(%s) + \"]\"\n%s}\n", p, openIndex(path), i, body)
+	case kindMap:
+		var keyPath string
+		switch typ.key.kind {
+		case kindString:
+			keyPath = toString(k, typ.key)
+		case kindInt:
+			keyPath = fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", k)
+		case kindUint:
+			keyPath = fmt.Sprintf("strconv.FormatUint(uint64(%s), 10)", k)
+		default:
+			return fmt.Errorf("cannot dive into map with keys of type '%s'", typ.key.expr)
+		}
+		keysBody := ""
+		if keys {
+			var err error
+			if keysBody, err = self.capture(func() error {
+				return self.genValue(k, typ.key, p, fieldName, keysFlags, false)
+			}); err != nil {
+				return err
+			}
+		}
+		body, err := self.capture(func() error {
+			return self.genValue(e, typ.elem, p, fieldName, flags, false)
+		})
+		if err != nil || keysBody+body == "" {
+			return err
+		}
+		if typ.key.kind != kindString {
+			self.imports["strconv"] = true
+		}
+		self.imports["sort"] = true
+		// the elements are validated in the order of their keys, as the engine does
+		ks := k + "s"
+		self.printf("%s := make([]%s, 0, len(%s))\n", ks, typ.key.expr, expr)
+		self.printf("for %s := range %s {\n%s = append(%s, %s)\n}\n", k, expr, ks, ks, k)
+		self.printf("sort.Slice(%s, func(a, b int) bool { return %s[a] < %s[b] })\n", ks, ks, ks)
+		self.printf("for _, %s := range %s {\n", k, ks)
+		self.printf("%s := %s + %s + \"]\"\n%s", p, openIndex(path), keyPath, keysBody)
+		if body != "" {
+			m := expr
+			if strings.HasPrefix(m, "*") {
+				m = "(" + m + ")"
+			}
+			self.printf("%s := %s[%s]\n%s", e, m, k, body)
+		}
+		self.printf("}\n")
+	default:
+		return fmt.Errorf("cannot dive into type '%s'", typ.expr)
+	}
+	return nil
+}
+
+// failCond returns the condition on which the value of expr fails the flag, empty if it never fails
+func (self *generator) failCond(f validator.Flag, expr string, typ *typeInfo) (string, error) {
+	if f.Negate || len(f.Alternatives) > 0 {
+		return "", fmt.Errorf("'%s' is not supported by validatorgen", f)
+	}
+	if validator.ControlFlag(f.Name) {
+		return "", fmt.Errorf("misplaced '%s'", f.Name)
+	}
+	if err := validator.CheckFlag(f, reflectType(typ)); err != nil {
+		return "", err
+	}
+	// the conditional validators check the zero value of the field itself
+	if cond, ok, err := self.conditionCond(f, expr, typ); ok || err != nil {
+		return cond, err
+	}
+	if f.Name == "required" {
+		return self.zeroCond(expr, typ)
+	}
+	// the other validators fail on nil pointers
+	ptr := typ.kind == kindPointer
+	v := expr
+	if ptr {
+		typ = typ.elem
+		v = "*" + expr
+	}
+	cond, err := self.valueCond(f, v, typ)
+	if err != nil || !ptr || self.notNil[expr] {
+		return cond, err
+	}
+	if cond == "" {
+		return expr + " == nil", nil
+	}
+	return fmt.Sprintf("%s == nil || %s", expr, cond), nil
+}
+
+// valueCond returns the condition on which the value of v, which isn't a pointer, fails the flag
+func (self *generator) valueCond(f validator.Flag, v string, typ *typeInfo) (string, error) {
+	switch {
+	case f.Name == "len":
+		params := strings.SplitN(f.Param, "-", 2)
+		args := make([]int, len(params))
+		for i, p := range params {
+			arg, err := strconv.Atoi(p)
+			if err != nil {
+				return "", fmt.Errorf("'%s': invalid param '%s'", f, f.Param)
+			}
+			args[i] = arg
+		}
+		if len(args) == 1 {
+			return fmt.Sprintf("len(%s) != %d", v, args[0]), nil
+		}
+		return fmt.Sprintf("len(%s) < %d || len(%s) > %d", v, args[0], v, args[1]), nil
+	case f.Name == "number":
+		if typ.kind == kindString {
+			return fmt.Sprintf("!validator.MatchString(%q, %q, %s)", f.Name, f.Param, toString(v, typ)), nil
+		}
+		return "", nil
+	case stringFlags[f.Name]:
+		return fmt.Sprintf("!validator.MatchString(%q, %q, %s)", f.Name, f.Param, toString(v, typ)), nil
+	case strings.HasSuffix(f.Name, "_field"):
+		target, ok := self.fields[f.Param]
+		if !ok {
+			return "", fmt.Errorf("'%s': field '%s' not found", f, f.Param)
+		}
+		t, targetTyp := "self."+target.name, target.typ
+		nilCond := ""
+		if targetTyp.kind == kindPointer {
+			nilCond = t + " == nil || "
+			t, targetTyp = "*"+t, targetTyp.elem
+		}
+		if compareKind(typ) != compareKind(targetTyp) {
+			return "", fmt.Errorf("'%s' cannot compare type '%s' with type '%s'", f.Name, typ.expr, targetTyp.expr)
+		}
+		a, err := convert(v, typ)
+		if err != nil {
+			return "", err
+		}
+		b, _ := convert(t, targetTyp)
+		return fmt.Sprintf("%s!(%s %s %s)", nilCond, a, opFlags[f.Name], b), nil
+	case opFlags[f.Name] != "":
+		lit, err := literal(f.Param, typ)
+		if err != nil {
+			return "", fmt.Errorf("'%s': %s", f, err)
+		}
+		a, err := convert(v, typ)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("!(%s %s %s)", a, opFlags[f.Name], lit), nil
+	}
+	return "", fmt.Errorf("'%s' is not supported by validatorgen", f.Name)
+}
+
+// conditionCond returns the condition of the conditional validators, ok is false for the other validators
+func (self *generator) conditionCond(f validator.Flag, expr string, typ *typeInfo) (cond string, ok bool, err error) {
+	var required, pairs, match, present bool
+	switch f.Name {
+	case "required_if":
+		required, pairs, match = true, true, true
+	case "required_unless":
+		required, pairs = true, true
+	case "required_with":
+		required, present = true, true
+	case "required_without":
+		required = true
+	case "excluded_if":
+		pairs, match = true, true
+	case "excluded_unless":
+		pairs = true
+	case "excluded_with":
+		present = true
+	case "excluded_without":
+	default:
+		return "", false, nil
+	}
+	zero, err := self.zeroCond(expr, typ)
+	if err != nil {
+		return "", true, err
+	}
+	if !required {
+		zero = not(zero)
+	}
+	items, err := validator.SplitParam(f.Param)
+	if err != nil {
+		return "", true, fmt.Errorf("'%s': %s", f, err)
+	}
+	var conds []string
+	if pairs {
+		for i := 0; i < len(items); i += 2 {
+			c, err := self.equalCond(items[i], items[i+1])
+			if err != nil {
+				return "", true, fmt.Errorf("'%s': %s", f, err)
+			}
+			conds = append(conds, c)
+		}
+		all := strings.Join(conds, " && ")
+		if match {
+			return fmt.Sprintf("%s && (%s)", zero, all), true, nil
+		}
+		return fmt.Sprintf("%s && !(%s)", zero, all), true, nil
+	}
+	for _, name := range items {
+		target, ok := self.fields[name]
+		if !ok {
+			return "", true, fmt.Errorf("'%s': field '%s' not found", f, name)
+		}
+		c, err := self.zeroCond("self."+target.name, target.typ)
+		if err != nil {
+			return "", true, fmt.Errorf("'%s': %s", f, err)
+		}
+		if present {
+			c = not(c)
+		}
+		conds = append(conds, c)
+	}
+	return fmt.Sprintf("%s && (%s)", zero, strings.Join(conds, " || ")), true, nil
+}
+
+// equalCond returns the condition on which the field named name equals the string form of a value
+func (self *generator) equalCond(name string, value string) (string, error) {
+	target, ok := self.fields[name]
+	if !ok {
+		return "", fmt.Errorf("field '%s' not found", name)
+	}
+	t, typ := "self."+target.name, target.typ
+	nilCond := ""
+	if typ.kind == kindPointer {
+		nilCond = t + " != nil && "
+		t, typ = "*"+t, typ.elem
+	}
+	switch typ.kind {
+	case kindString:
+		return fmt.Sprintf("%s%s == %s", nilCond, t, strconv.Quote(value)), nil
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "false", nil
+		}
+		if b {
+			return nilCond + t, nil
+		}
+		return nilCond + "!" + t, nil
+	case kindInt, kindUint, kindFloat:
+		lit, err := literal(value, &typeInfo{kind: typ.kind, bits: 64})
+		if err != nil {
+			// a value that can't be parsed never matches
+			return "false", nil
+		}
+		a, _ := convert(t, typ)
+		return fmt.Sprintf("%s%s == %s", nilCond, a, lit), nil
+	}
+	return "", fmt.Errorf("cannot compare field '%s' of type '%s'", name, typ.expr)
+}
+
+// zeroCond returns the condition on which the value of expr is the zero value of its type
+func (self *generator) zeroCond(expr string, typ *typeInfo) (string, error) {
+	switch typ.kind {
+	case kindString:
+		return expr + ` == ""`, nil
+	case kindInt, kindUint, kindFloat:
+		return expr + " == 0", nil
+	case kindBool:
+		return "!" + expr, nil
+	case kindPointer, kindSlice, kindMap, kindInterface:
+		return expr + " == nil", nil
+	case kindTime:
+		self.imports["time"] = true
+		return expr + " == (time.Time{})", nil
+	}
+	return "", fmt.Errorf("cannot check the zero value of type '%s'", typ.expr)
+}
+
+// compareKind returns the kind the *_field validators compare the values of typ as, kindOther if they don't
+func compareKind(typ *typeInfo) kind {
+	switch typ.kind {
+	case kindString, kindInt, kindUint, kindFloat:
+		return typ.kind
+	}
+	return kindOther
+}
+
+// not negates a condition of zeroCond
+func not(cond string) string {
+	if strings.HasPrefix(cond, "!") {
+		return cond[1:]
+	}
+	return strings.Replace(cond, " == ", " != ", 1)
+}
+
+// toString converts the value of v, of a string type, to string
+func toString(v string, typ *typeInfo) string {
+	if typ.expr == "string" {
+		return v
+	}
+	return "string(" + v + ")"
+}
+
+// openIndex returns the expression of path followed by "[", for the paths of the elements
+func openIndex(path string) string {
+	if strings.HasSuffix(path, `"`) {
+		return path[:len(path)-1] + `["`
+	}
+	return path + ` + "["`
+}
+
+// convert converts the value of v to the type it is compared as
+func convert(v string, typ *typeInfo) (string, error) {
+	switch typ.kind {
+	case kindString:
+		return v, nil
+	case kindInt:
+		return "int64(" + v + ")", nil
+	case kindUint:
+		return "uint64(" + v + ")", nil
+	case kindFloat:
+		return "float64(" + v + ")", nil
+	}
+	return "", fmt.Errorf("comparing type '%s' is not supported by validatorgen", typ.expr)
+}
+
+// literal returns the Go literal of a param parsed as the engine does for typ
+func literal(param string, typ *typeInfo) (string, error) {
+	switch typ.kind {
+	case kindString:
+		return strconv.Quote(param), nil
+	case kindInt:
+		n, err := strconv.ParseInt(param, 0, 64)
+		return strconv.FormatInt(n, 10), err
+	case kindUint:
+		n, err := strconv.ParseUint(param, 0, 64)
+		return strconv.FormatUint(n, 10), err
+	case kindFloat:
+		f, err := strconv.ParseFloat(param, typ.bits)
+		if err != nil {
+			return "", err
+		}
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		if strings.ContainsAny(s, "NI") {
+			return "", fmt.Errorf("invalid param '%s'", param)
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("comparing type '%s' is not supported by validatorgen", typ.expr)
+}
+
+// ruleText formats the flag for validator.Failures.Fail, quoting the param if needed
+func ruleText(f validator.Flag) string {
+	if f.Param == "" || !strings.ContainsAny(f.Param, ",|'\\!: ") {
+		return f.String()
+	}
+	param := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(f.Param)
+	return f.Name + ":'" + param + "'"
+}
+
+// resolve returns the type of a type expression of the package
+func (self *generator) resolve(expr ast.Expr) *typeInfo {
+	src := types.ExprString(expr)
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if typ, ok := basicTypes[e.Name]; ok {
+			typ.expr = src
+			return &typ
+		}
+		spec, ok := self.specs[e.Name]
+		if !ok {
+			return &typeInfo{kind: kindOther, expr: src}
+		}
+		if _, ok := spec.Type.(*ast.StructType); ok {
+			return &typeInfo{kind: kindStruct, expr: src, name: e.Name}
+		}
+		if spec.Assign.IsValid() {
+			return self.resolve(spec.Type)
+		}
+		typ := *self.resolve(spec.Type)
+		typ.expr = src
+		return &typ
+	case *ast.StarExpr:
+		return &typeInfo{kind: kindPointer, expr: src, elem: self.resolve(e.X)}
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return &typeInfo{kind: kindSlice, expr: src, elem: self.resolve(e.Elt)}
+		}
+		return &typeInfo{kind: kindArray, expr: src, elem: self.resolve(e.Elt)}
+	case *ast.MapType:
+		return &typeInfo{kind: kindMap, expr: src, key: self.resolve(e.Key), elem: self.resolve(e.Value)}
+	case *ast.SelectorExpr:
+		if src == "time.Time" {
+			return &typeInfo{kind: kindTime, expr: src}
+		}
+		// the fields of the structs of other packages are not in the source
+		if typ := self.info.TypeOf(e); typ != nil {
+			if _, ok := typ.Underlying().(*types.Struct); ok {
+				return &typeInfo{kind: kindStruct, expr: src}
+			}
+		}
+	case *ast.InterfaceType:
+		return &typeInfo{kind: kindInterface, expr: src}
+	}
+	return &typeInfo{kind: kindOther, expr: src}
+}
+
+var basicTypes = map[string]typeInfo{
+	"string":  {kind: kindString},
+	"bool":    {kind: kindBool},
+	"int":     {kind: kindInt},
+	"int8":    {kind: kindInt},
+	"int16":   {kind: kindInt},
+	"int32":   {kind: kindInt},
+	"rune":    {kind: kindInt},
+	"int64":   {kind: kindInt},
+	"uint":    {kind: kindUint},
+	"uint8":   {kind: kindUint},
+	"byte":    {kind: kindUint},
+	"uint16":  {kind: kindUint},
+	"uint32":  {kind: kindUint},
+	"uint64":  {kind: kindUint},
+	"uintptr": {kind: kindUint},
+	"float32": {kind: kindFloat, bits: 32},
+	"float64": {kind: kindFloat, bits: 64},
+	"any":     {kind: kindInterface},
+}
+
+// reflectType returns a type of the same kind as typ for validator.CheckFlag, nil if it's unknown
+func reflectType(typ *typeInfo) reflect.Type {
+	switch typ.kind {
+	case kindString:
+		return reflect.TypeOf("")
+	case kindInt:
+		return reflect.TypeOf(int64(0))
+	case kindUint:
+		return reflect.TypeOf(uint64(0))
+	case kindFloat:
+		if typ.bits == 32 {
+			return reflect.TypeOf(float32(0))
+		}
+		return reflect.TypeOf(float64(0))
+	case kindBool:
+		return reflect.TypeOf(false)
+	case kindTime:
+		return reflect.TypeOf(time.Time{})
+	case kindStruct:
+		return reflect.TypeOf(struct{}{})
+	case kindPointer:
+		if elem := reflectType(typ.elem); elem != nil {
+			return reflect.PointerTo(elem)
+		}
+	case kindSlice:
+		return reflect.TypeOf([]interface{}(nil))
+	case kindArray:
+		return reflect.TypeOf([0]interface{}{})
+	case kindMap:
+		return reflect.TypeOf(map[string]interface{}(nil))
+	}
+	return nil
+}
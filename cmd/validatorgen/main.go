@@ -0,0 +1,110 @@
+// Command validatorgen generates Validate methods from the validate tags of struct types, so they
+// are validated without reflection. Install it from the cmd directory of the repository and add
+// a go:generate directive to the package of the types:
+//
+//	//go:generate validatorgen -type User,Address
+//
+// The generated methods return the same errors as Engine.Validate with the default settings.
+// Only calling them directly skips reflection, Engine.Validate still validates the tags itself,
+// see validator.Generated.
+// The builtin validators are supported, except for OR groups, negated rules and the comparisons
+// of time.Time; the nested structs must be of the package and generated as well.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names, required")
+	output    = flag.String("output", "", "output file name, default <first type>_validator.go")
+	tagName   = flag.String("tag", "validate", "the key of the validator tags")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: validatorgen -type T1,T2 [-output file] [-tag validate] [directory]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	types := strings.Split(*typeNames, ",")
+	outputName := *output
+	if outputName == "" {
+		outputName = strings.ToLower(types[0]) + "_validator.go"
+	}
+	if !filepath.IsAbs(outputName) {
+		outputName = filepath.Join(dir, outputName)
+	}
+	pkgName, files, info, err := loadPackage(dir, outputName)
+	if err != nil {
+		fatal(err)
+	}
+	src, err := generate(pkgName, files, info, types, *tagName)
+	if err != nil {
+		fatal(err)
+	}
+	if err := os.WriteFile(outputName, src, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "validatorgen: %s\n", err)
+	os.Exit(1)
+}
+
+// loadPackage loads the syntax and the types of the package in dir, except the tests, the output file and
+// the other generated files. The type errors are ignored, e.g. of a generated file out of date, only the
+// types of the imported packages are looked up
+func loadPackage(dir string, outputName string) (string, []*ast.File, *types.Info, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedCompiledGoFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, nil, fmt.Errorf("%d packages found in '%s'", len(pkgs), dir)
+	}
+	pkg := pkgs[0]
+	for _, err := range pkg.Errors {
+		if err.Kind != packages.TypeError {
+			return "", nil, nil, err
+		}
+	}
+	output, err := filepath.Abs(outputName)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	files := make([]*ast.File, 0, len(pkg.Syntax))
+	for i, file := range pkg.Syntax {
+		if filepath.Clean(pkg.CompiledGoFiles[i]) == output {
+			continue
+		}
+		if len(file.Comments) > 0 && file.Comments[0].Pos() < file.Package && file.Comments[0].Text() == header {
+			continue
+		}
+		files = append(files, file)
+	}
+	return pkg.Name, files, pkg.TypesInfo, nil
+}
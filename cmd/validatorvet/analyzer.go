@@ -0,0 +1,291 @@
+package main
+
+import (
+	"github.com/shaopson/validator"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "validatorvet",
+	Doc:      "check the validate tags of struct fields",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// tagName is the key of the tags, see Engine.SetTagName
+var tagName string
+
+// customValidators are the names of the validators registered by Engine.RegisterValidator,
+// separated by commas. Their params and types are not checked
+var customValidators string
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", "validate", "the key of the validator tags")
+	Analyzer.Flags.StringVar(&customValidators, "validators", "", "comma-separated names of the custom validators")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	custom := make(map[string]bool)
+	for _, name := range strings.Split(customValidators, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			custom[name] = true
+		}
+	}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		node := n.(*ast.StructType)
+		structTyp, ok := pass.TypesInfo.TypeOf(node).(*types.Struct)
+		if !ok {
+			return
+		}
+		for _, field := range node.Fields.List {
+			if field.Tag == nil || !exported(field) {
+				continue
+			}
+			s, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			tag, ok := reflect.StructTag(s).Lookup(tagName)
+			if !ok || tag == "-" {
+				continue
+			}
+			c := &checker{pass: pass, pos: field.Tag.Pos(), structTyp: structTyp, custom: custom}
+			flags, err := validator.ParseTag(tag)
+			if err != nil {
+				c.report(err)
+				continue
+			}
+			c.checkFlags(flags, pass.TypesInfo.TypeOf(field.Type))
+		}
+	})
+	return nil, nil
+}
+
+// exported reports whether the engine validates the field, the tags of the unexported fields are ignored
+func exported(field *ast.Field) bool {
+	if len(field.Names) == 0 {
+		return true
+	}
+	for _, name := range field.Names {
+		if name.IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// checker checks the tag of a field
+type checker struct {
+	pass      *analysis.Pass
+	pos       token.Pos
+	structTyp *types.Struct
+	// custom are the validators of the -validators flag
+	custom map[string]bool
+}
+
+func (self *checker) report(err error) {
+	self.pass.Reportf(self.pos, "%s", err)
+}
+
+// checkFlags checks the flags applied to a value of typ, the flags after a dive apply to the elements
+func (self *checker) checkFlags(flags []validator.Flag, typ types.Type) {
+	for i, flag := range flags {
+		switch flag.Name {
+		case "dive":
+			self.checkDive(flags[i+1:], typ)
+			return
+		case "blank":
+			continue
+		}
+		self.checkFlag(flag, typ)
+	}
+}
+
+func (self *checker) checkDive(flags []validator.Flag, typ types.Type) {
+	if typ == nil {
+		return
+	}
+	var keyTyp, elemTyp types.Type
+	switch t := deref(typ).Underlying().(type) {
+	case *types.Map:
+		keyTyp, elemTyp = t.Key(), t.Elem()
+	case *types.Slice:
+		elemTyp = t.Elem()
+	case *types.Array:
+		elemTyp = t.Elem()
+	case *types.Interface:
+		return
+	default:
+		self.pass.Reportf(self.pos, "cannot dive into type '%s'", typ)
+		return
+	}
+	if len(flags) > 0 && flags[0].Name == "keys" {
+		end := -1
+		for i, flag := range flags {
+			if flag.Name == "endkeys" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			self.pass.Reportf(self.pos, "'keys' without 'endkeys'")
+			return
+		}
+		if keyTyp == nil {
+			self.pass.Reportf(self.pos, "'keys' only support map type")
+			return
+		}
+		self.checkFlags(flags[1:end], keyTyp)
+		flags = flags[end+1:]
+	}
+	self.checkFlags(flags, elemTyp)
+}
+
+func (self *checker) checkFlag(flag validator.Flag, typ types.Type) {
+	for _, alternative := range flag.Alternatives {
+		self.checkFlag(alternative, typ)
+	}
+	if len(flag.Alternatives) > 0 {
+		return
+	}
+	if validator.ControlFlag(flag.Name) {
+		self.pass.Reportf(self.pos, "misplaced '%s'", flag.Name)
+		return
+	}
+	if self.custom[flag.Name] {
+		return
+	}
+	if err := validator.CheckFlag(flag, reflectType(typ)); err != nil {
+		self.report(err)
+		return
+	}
+	if param, ok := validator.FieldParamOf(flag.Name); ok {
+		self.checkFields(flag, param, typ)
+	}
+}
+
+// checkFields checks the fields named by the param of flag
+func (self *checker) checkFields(flag validator.Flag, param validator.FieldParam, typ types.Type) {
+	names, err := validator.SplitParam(flag.Param)
+	if err != nil {
+		self.pass.Reportf(self.pos, "'%s': %s", flag, err)
+		return
+	}
+	step := 1
+	if param.Pairs {
+		if len(names)%2 != 0 {
+			self.pass.Reportf(self.pos, "'%s': invalid param '%s'", flag, flag.Param)
+			return
+		}
+		step = 2
+	}
+	if len(names) == 0 {
+		self.pass.Reportf(self.pos, "'%s': missing param", flag)
+		return
+	}
+	for i := 0; i < len(names); i += step {
+		target := self.field(names[i])
+		if target == nil {
+			self.pass.Reportf(self.pos, "'%s': field '%s' not found", flag, names[i])
+			continue
+		}
+		if param.Compare && typ != nil && typeClass(target.Type()) != typeClass(typ) {
+			self.pass.Reportf(self.pos, "'%s' cannot compare type '%s' with type '%s'", flag.Name, typ, target.Type())
+		}
+	}
+}
+
+// field returns the field of the struct named name, including the promoted fields
+func (self *checker) field(name string) *types.Var {
+	obj, _, _ := types.LookupFieldOrMethod(self.structTyp, false, nil, name)
+	if field, ok := obj.(*types.Var); ok && field.IsField() {
+		return field
+	}
+	// unexported names are only found with their package
+	for i := 0; i < self.structTyp.NumFields(); i++ {
+		if field := self.structTyp.Field(i); field.Name() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func deref(typ types.Type) types.Type {
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return typ
+}
+
+func isTime(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time"
+}
+
+// reflectType returns a type of the same kind as typ for validator.CheckFlag, nil if it's only known at run time
+func reflectType(typ types.Type) reflect.Type {
+	if typ == nil {
+		return nil
+	}
+	typ = deref(typ)
+	if isTime(typ) {
+		return reflect.TypeOf(time.Time{})
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		if kind, ok := basicKinds[t.Kind()]; ok {
+			return kind
+		}
+		return nil
+	case *types.Slice:
+		return reflect.TypeOf([]interface{}(nil))
+	case *types.Array:
+		return reflect.TypeOf([0]interface{}{})
+	case *types.Map:
+		return reflect.TypeOf(map[string]interface{}(nil))
+	case *types.Struct:
+		return reflect.TypeOf(struct{}{})
+	case *types.Chan:
+		return reflect.TypeOf((chan int)(nil))
+	case *types.Signature:
+		return reflect.TypeOf((func())(nil))
+	}
+	return nil
+}
+
+var basicKinds = map[types.BasicKind]reflect.Type{
+	types.Bool:    reflect.TypeOf(false),
+	types.Int:     reflect.TypeOf(int(0)),
+	types.Int8:    reflect.TypeOf(int8(0)),
+	types.Int16:   reflect.TypeOf(int16(0)),
+	types.Int32:   reflect.TypeOf(int32(0)),
+	types.Int64:   reflect.TypeOf(int64(0)),
+	types.Uint:    reflect.TypeOf(uint(0)),
+	types.Uint8:   reflect.TypeOf(uint8(0)),
+	types.Uint16:  reflect.TypeOf(uint16(0)),
+	types.Uint32:  reflect.TypeOf(uint32(0)),
+	types.Uint64:  reflect.TypeOf(uint64(0)),
+	types.Uintptr: reflect.TypeOf(uintptr(0)),
+	types.Float32: reflect.TypeOf(float32(0)),
+	types.Float64: reflect.TypeOf(float64(0)),
+	types.String:  reflect.TypeOf(""),
+}
+
+// typeClass groups the types the *_field validators compare alike, see validator.CompareClass
+func typeClass(typ types.Type) string {
+	t := reflectType(typ)
+	if t == nil {
+		return ""
+	}
+	return validator.CompareClass(t)
+}
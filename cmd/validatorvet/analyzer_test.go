@@ -0,0 +1,18 @@
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/analysistest"
+	"testing"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestAnalyzerValidators(t *testing.T) {
+	if err := Analyzer.Flags.Set("validators", "uuid, slug"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("validators", "")
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "b")
+}
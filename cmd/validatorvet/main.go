@@ -0,0 +1,18 @@
+// Command validatorvet reports the mistakes in the validate tags of struct fields at compile time:
+// unknown validators, params that can't be parsed, *_field and conditional validators naming missing
+// fields, and validators applied to types they don't support.
+//
+// Run it alone or with go vet:
+//
+//	validatorvet ./...
+//	go vet -vettool=$(which validatorvet) ./...
+//
+// The validators registered by Engine.RegisterValidator are named by the -validators flag,
+// e.g. -validators=uuid,slug, they are unregistered otherwise.
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
@@ -0,0 +1,39 @@
+package a
+
+import "time"
+
+type Address struct {
+	Zip string `validate:"len:6,number"`
+}
+
+type User struct {
+	Name     string         `validate:"requred"` // want `Unregistered validator 'requred'`
+	Nick     string         `validate:"len:abc"` // want `'len:abc': invalid param 'abc'`
+	Code     string         `validate:"len:6-x"` // want `'len:6-x': invalid param '6-x'`
+	Age      int            `validate:"email"`   // want `'email' not support type 'int'`
+	Password string         `validate:"required,len:8-20"`
+	Confirm  string         `validate:"eq_field:Pasword"` // want `'eq_field:Pasword': field 'Pasword' not found`
+	Retry    string         `validate:"eq_field:Password"`
+	MinAge   string         `validate:"lt_field:Age"` // want `'lt_field' cannot compare type 'string' with type 'int'`
+	Start    time.Time      `validate:"lt_field:End"`
+	End      *time.Time     `validate:"gt:2020-01-01"`
+	Score    float64        `validate:"gt:x"`        // want `'gt:x': strconv.ParseFloat`
+	IP       string         `validate:"ip:v5|email"` // want `'ip:v5': invalid param 'v5'`
+	Tags     []string       `validate:"dive,lower"`
+	Counts   []int          `validate:"dive,upper"` // want `'upper' not support type 'int'`
+	Limits   map[string]int `validate:"dive,keys,len:2-4,endkeys,gte:0"`
+	Bad      map[string]int `validate:"dive,keys,len:2-4"`          // want `'keys' without 'endkeys'`
+	Slice    []string       `validate:"dive,keys,required,endkeys"` // want `'keys' only support map type`
+	Single   string         `validate:"dive,required"`              // want `cannot dive into type 'string'`
+	Quote    string         `validate:"'required"`                  // want `unterminated quote`
+	Tax      string         `validate:"required_if:Kind business"`  // want `field 'Kind' not found`
+	Pair     string         `validate:"required_if:Name"`           // want `invalid param 'Name'`
+	Phone    string         `validate:"required_without:Email"`
+	Email    string         `validate:"blank,!lower"`
+	Any      interface{}    `validate:"gt:1"`
+	Address  Address
+	Items    []Address         `validate:"dive"`
+	Extra    map[string]string `validate:"-"`
+	hidden   int               `validate:"email"`
+	Other    string            `json:"other"`
+}
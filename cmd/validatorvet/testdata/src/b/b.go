@@ -0,0 +1,8 @@
+package b
+
+type User struct {
+	ID    string `validate:"required,uuid"`
+	Slug  string `validate:"blank,slug:x|len:2-20"`
+	Email string `validate:"email,mx"`           // want `Unregistered validator 'mx'`
+	Phone string `validate:"phone|uuid,len:abc"` // want `'len:abc': invalid param 'abc'`
+}
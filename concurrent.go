@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// resultKey identifies a run of a rule on a value, by the path of the value
+type resultKey struct {
+	rule *rule
+	path string
+}
+
+// job is a run of an expensive validator, see Engine.SetConcurrency
+type job struct {
+	key  resultKey
+	v    *Validation
+	err  error
+	done bool
+}
+
+// prefetch collects the expensive validators of a struct, they are run on a worker pool
+// before the validation, which then takes their results in declaration order
+type prefetch struct {
+	jobs []*job
+}
+
+// add collects the expensive rules of a field, the alternatives of the OR groups included
+func (self *prefetch) add(ctx context.Context, rules []*rule, path string, fieldTyp reflect.StructField, field reflect.Value, structVal reflect.Value) {
+	for _, rule := range rules {
+		if len(rule.alternatives) > 0 {
+			self.add(ctx, rule.alternatives, path, fieldTyp, field, structVal)
+			continue
+		}
+		if rule.expensive {
+			self.jobs = append(self.jobs, &job{
+				key: resultKey{rule: rule, path: path},
+				v:   newValidation(ctx, rule, fieldTyp, field, structVal),
+			})
+		}
+	}
+}
+
+// RegisterExpensiveValidator registers a validator that is slow to run, e.g. a lookup in a database.
+// Once SetConcurrency is set, the expensive validators of a struct run concurrently and their
+// feedbacks are reported in declaration order. They must be safe to call concurrently
+func (self *Engine) RegisterExpensiveValidator(flag string, validator Validator) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.Validators[flag] = validator
+	self.expensive[flag] = true
+	self.resetPlans()
+}
+
+// SetConcurrency makes Validate and ValidateCtx run the expensive validators on n workers, 0 runs
+// them in order with the others. The expensive validators of the struct all run before the feedbacks
+// are collected, so the fail fast modes and the max errors limit don't save their calls, nor does an
+// alternative of an OR group passing before them, e.g. unique runs for "blank_email|unique" on ""
+func (self *Engine) SetConcurrency(n int) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.concurrency = n
+}
+
+// runExpensive runs the expensive validators of structVal on n workers and returns their results.
+// The panic of a validator is raised again in the calling goroutine
+func (self *Engine) runExpensive(ctx context.Context, structVal reflect.Value, n int) (map[resultKey]error, error) {
+	p := &ValidationError{
+		Detail:   make([]*FieldError, 0),
+		ctx:      ctx,
+		prefetch: &prefetch{},
+	}
+	if err := self.validateStruct(structVal, "", false, p); err != nil {
+		return nil, err
+	}
+	jobs := p.prefetch.jobs
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	if n > len(jobs) {
+		n = len(jobs)
+	}
+	queue := make(chan *job)
+	panics := make(chan interface{}, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+					// let the other workers drain the queue
+					for range queue {
+					}
+				}
+			}()
+			for j := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				j.err = j.key.rule.validator(j.v)
+				j.done = true
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+	select {
+	case r := <-panics:
+		panic(r)
+	default:
+	}
+	results := make(map[resultKey]error, len(jobs))
+	for _, j := range jobs {
+		if j.done {
+			results[j.key] = j.err
+		}
+	}
+	return results, nil
+}
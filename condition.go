@@ -0,0 +1,205 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// condition is the param of the conditional validators, the sibling fields
+// with the values they are compared to. values is nil for the *_with validators
+type condition struct {
+	names  []string
+	fields []fieldIndex
+	values []string
+}
+
+// parseCondition parses "Field1 value1 Field2 value2" if pairs is set, otherwise "Field1 Field2"
+func parseCondition(param string, structTyp reflect.Type, pairs bool) (*condition, error) {
+	if structTyp == nil {
+		return nil, fmt.Errorf("no struct to find fields '%s'", param)
+	}
+	cond, err := splitCondition(param, pairs)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range cond.names {
+		field, ok := structTyp.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("param error: field '%s' not found", name)
+		}
+		cond.fields[i] = fieldIndex(field.Index)
+	}
+	return cond, nil
+}
+
+// splitCondition splits the param into the names and the values, the fields are left unresolved
+func splitCondition(param string, pairs bool) (*condition, error) {
+	items, err := SplitParam(param)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("missing param")
+	}
+	step := 1
+	if pairs {
+		if len(items)%2 != 0 {
+			return nil, fmt.Errorf("invalid param '%s'", param)
+		}
+		step = 2
+	}
+	cond := &condition{}
+	for i := 0; i < len(items); i += step {
+		cond.names = append(cond.names, items[i])
+		cond.fields = append(cond.fields, nil)
+		if pairs {
+			cond.values = append(cond.values, items[i+1])
+		}
+	}
+	return cond, nil
+}
+
+// SplitParam splits the param of the conditional validators into the field names and the values,
+// separated by spaces. A value that is empty or has spaces is double quoted as a Go string:
+//
+//	required_if:Kind "a b" Note ""
+//
+// For the tools that read the params, see CheckFlag
+func SplitParam(param string) ([]string, error) {
+	items := make([]string, 0)
+	for s := strings.TrimLeftFunc(param, unicode.IsSpace); s != ""; s = strings.TrimLeftFunc(s, unicode.IsSpace) {
+		if s[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid param '%s'", param)
+			}
+			s = s[len(quoted):]
+			if s != "" && !unicode.IsSpace(rune(s[0])) {
+				return nil, fmt.Errorf("invalid param '%s'", param)
+			}
+			item, _ := strconv.Unquote(quoted)
+			items = append(items, item)
+			continue
+		}
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			end = len(s)
+		}
+		items = append(items, s[:end])
+		s = s[end:]
+	}
+	return items, nil
+}
+
+func (self *Validation) conditionParam(pairs bool) (*condition, error) {
+	if arg, ok := self.arg.(*condition); ok {
+		return arg, nil
+	}
+	if !self.Struct.IsValid() {
+		return nil, fmt.Errorf("no struct to find fields '%s'", self.Param)
+	}
+	return parseCondition(self.Param, self.Struct.Type(), pairs)
+}
+
+// matchAll reports whether every field equals its value
+func (self *condition) matchAll(structVal reflect.Value) bool {
+	for i, index := range self.fields {
+		field, err := structVal.FieldByIndexErr(index)
+		if err != nil || !valueEquals(field, self.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyPresent reports whether any of the fields is not zero
+func (self *condition) anyPresent(structVal reflect.Value) bool {
+	for _, index := range self.fields {
+		if field, err := structVal.FieldByIndexErr(index); err == nil && !field.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// anyAbsent reports whether any of the fields is zero
+func (self *condition) anyAbsent(structVal reflect.Value) bool {
+	for _, index := range self.fields {
+		if field, err := structVal.FieldByIndexErr(index); err != nil || field.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// pairs describes the field/value pairs, e.g. Type is business and Kind is shop
+func (self *condition) pairs(fieldName func(string) string) string {
+	items := make([]string, len(self.names))
+	for i, name := range self.names {
+		items[i] = fmt.Sprintf("%s is %s", fieldName(name), self.values[i])
+	}
+	return strings.Join(items, " and ")
+}
+
+// fieldNames describes the fields, e.g. Phone or Email
+func (self *condition) fieldNames(fieldName func(string) string) string {
+	items := make([]string, len(self.names))
+	for i, name := range self.names {
+		items[i] = fieldName(name)
+	}
+	return strings.Join(items, " or ")
+}
+
+// params returns the fields, with the values for the *_if and *_unless validators,
+// as the params of the feedback
+func (self *condition) params(fieldName func(string) string) map[string]any {
+	names := make([]string, len(self.names))
+	for i, name := range self.names {
+		names[i] = fieldName(name)
+	}
+	params := map[string]any{"fields": names}
+	if self.values != nil {
+		params["values"] = append([]string(nil), self.values...)
+	}
+	return params
+}
+
+// valueEquals compares field with the string form of a value
+func valueEquals(field reflect.Value, s string) bool {
+	if field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return false
+		}
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String() == s
+	case reflect.Struct:
+		// times are written as the params of the comparison validators, in UTC to the second
+		if field.Type().ConvertibleTo(timeType) {
+			t, err := parseTimeParam(s)
+			return err == nil && field.Convert(timeType).Interface().(time.Time).Truncate(time.Second).Equal(t)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(s, 0, 64)
+		return err == nil && field.Int() == value
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(s, 0, 64)
+		return err == nil && field.Uint() == value
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(s, 64)
+		return err == nil && field.Float() == value
+	case reflect.Bool:
+		value, err := strconv.ParseBool(s)
+		return err == nil && field.Bool() == value
+	}
+	if field.CanInterface() {
+		return fmt.Sprint(field.Interface()) == s
+	}
+	return false
+}
@@ -2,11 +2,47 @@ package validator
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 )
 
+var (
+	// ErrUnregisteredValidator is returned when a tag uses a validator that isn't registered
+	ErrUnregisteredValidator = errors.New("unregistered validator")
+	// ErrInvalidParam is returned when the param of a validator can't be parsed
+	ErrInvalidParam = errors.New("invalid param")
+	// ErrUnsupportedType is returned when a validator or a flag is applied to a type it doesn't support
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrInvalidTag is returned when a tag can't be parsed
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrInvalidValue is returned when the value to validate is a nil pointer or not a struct
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidRules is returned when a rules document can't be decoded or names unknown types or fields
+	ErrInvalidRules = errors.New("invalid rules")
+)
+
+// ConfigError is an error of the configuration of the engine, or of its usage, rather than of the
+// validated values. It wraps one of the Err* errors, use errors.Is to tell them apart
+type ConfigError struct {
+	// Field is the name of the field, empty if the error isn't about a field
+	Field string
+	// Flag is the name of the validator, empty if the error isn't about a validator
+	Flag string
+	Err  error
+	s    string
+}
+
+func (self *ConfigError) Error() string {
+	return self.s
+}
+
+func (self *ConfigError) Unwrap() error {
+	return self.Err
+}
+
 type FeedbackHandler func(f *Feedback) string
 
 type Translation interface {
@@ -15,21 +51,52 @@ type Translation interface {
 
 type Feedback struct {
 	Validation *Validation
-	s          string
+	// Code identifies the failure for clients that render their own messages,
+	// it's the flag of the validator unless the validator gives a more specific one, e.g. len.range
+	Code string
+	// Params holds the typed params of the failure, e.g. {"min": 6, "max": 18} for len:6-18
+	Params map[string]any
+	// Value is the offending value, nil if the field is a nil pointer
+	Value any
+	s     string
+	// the feedbacks of the alternatives of a failed OR group
+	alternatives []*Feedback
 }
 
 func (self *Feedback) Error() string {
 	return self.s
 }
 
+// Alternatives returns the feedbacks of each alternative when an OR group fails, e.g. ip:v4|email
+func (self *Feedback) Alternatives() []*Feedback {
+	return self.alternatives
+}
+
 type FieldError struct {
-	Field     reflect.StructField
+	Field reflect.StructField
+	// Name is the name of the field, see Engine.RegisterTagNameFunc
+	Name string
+	// Path is the dotted path of the field from the validated struct, e.g. Shipping.Address.Zip
+	Path      string
 	Feedbacks []*Feedback
 	s         string
 }
 
 func (self *FieldError) Error() string {
-	return fmt.Sprintf("%s: %s", self.Field.Name, self.string())
+	// the error of a single value validated by Engine.Var has no path
+	if self.Path == "" {
+		return self.string()
+	}
+	return fmt.Sprintf("%s: %s", self.Path, self.string())
+}
+
+// Unwrap returns the feedbacks, so errors.As finds them
+func (self *FieldError) Unwrap() []error {
+	errs := make([]error, len(self.Feedbacks))
+	for i, f := range self.Feedbacks {
+		errs[i] = f
+	}
+	return errs
 }
 
 func (self *FieldError) Translate(t Translation) string {
@@ -56,15 +123,44 @@ func (self *FieldError) string() string {
 }
 
 type ValidationError struct {
-	Detail      []*FieldError
+	Detail []*FieldError
+	// Truncated reports whether the validation stopped early because of the fail fast
+	// modes or the max errors limit, so Detail may not contain every invalid field
+	Truncated   bool
 	translation Translation
+	limits      limits
+	feedbacks   int
+	stop        bool
+	// ctx is the context of ValidateCtx, nil for the other validations
+	ctx context.Context
+	// prefetch collects the expensive validators instead of validating, results are their results
+	prefetch *prefetch
+	results  map[resultKey]error
+}
+
+// call runs the validator of rule, unless it was run concurrently, see Engine.SetConcurrency
+func (self *ValidationError) call(rule *rule, path string, v *Validation) error {
+	if err, ok := self.results[resultKey{rule: rule, path: path}]; ok {
+		return err
+	}
+	return rule.validator(v)
+}
+
+// ctxErr returns the error of the context once it's done
+func (self *ValidationError) ctxErr() error {
+	if self.ctx == nil {
+		return nil
+	}
+	return self.ctx.Err()
 }
 
 func (self *ValidationError) Error() string {
 	buf := bytes.NewBufferString("")
 	for _, e := range self.Detail {
 		if self.translation != nil {
-			buf.WriteString(e.Field.Name + ": ")
+			if e.Path != "" {
+				buf.WriteString(e.Path + ": ")
+			}
 			buf.WriteString(e.Translate(self.translation))
 		} else {
 			buf.WriteString(e.Error())
@@ -74,14 +170,29 @@ func (self *ValidationError) Error() string {
 	return strings.TrimSpace(buf.String())
 }
 
+// Unwrap returns the field errors, so errors.As finds them and their feedbacks
+func (self *ValidationError) Unwrap() []error {
+	errs := make([]error, len(self.Detail))
+	for i, e := range self.Detail {
+		errs[i] = e
+	}
+	return errs
+}
+
 func (self *ValidationError) Map() map[string]string {
 	result := make(map[string]string)
 	for _, e := range self.Detail {
+		var s string
 		if self.translation != nil {
-			result[e.Field.Name] = e.Translate(self.translation)
+			s = e.Translate(self.translation)
 		} else {
-			result[e.Field.Name] = e.string()
+			s = e.string()
+		}
+		// the key and the value of a map element share the same path
+		if prev, ok := result[e.Path]; ok {
+			s = prev + ";" + s
 		}
+		result[e.Path] = s
 	}
 	return result
 }
@@ -89,3 +200,27 @@ func (self *ValidationError) Map() map[string]string {
 func (self *ValidationError) SetTranslation(t Translation) {
 	self.translation = t
 }
+
+// addFeedback adds feedback to the FieldError of path, creating it if needed,
+// unless the max errors limit is reached
+func (self *ValidationError) addFeedback(field reflect.StructField, name string, path string, feedback *Feedback) {
+	if self.limits.maxErrors > 0 && self.feedbacks >= self.limits.maxErrors {
+		self.stop = true
+		self.Truncated = true
+		return
+	}
+	self.feedbacks++
+	for _, e := range self.Detail {
+		if e.Path == path {
+			e.Feedbacks = append(e.Feedbacks, feedback)
+			e.s = ""
+			return
+		}
+	}
+	self.Detail = append(self.Detail, &FieldError{
+		Field:     field,
+		Name:      name,
+		Path:      path,
+		Feedbacks: []*Feedback{feedback},
+	})
+}
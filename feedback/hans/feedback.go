@@ -3,6 +3,7 @@ package hans
 import (
 	"fmt"
 	"github.com/shaopson/validator"
+	"strings"
 	"sync"
 )
 
@@ -30,6 +31,17 @@ var defaultFeedbackHandlers = map[string]validator.FeedbackHandler{
 	"prefix":    prefixFeedback,
 	"suffix":    suffixFeedback,
 	"password":  passwordFeedback,
+	"not":       notFeedback,
+	"type":      typeFeedback,
+
+	"required_if":      requiredIfFeedback,
+	"required_unless":  requiredUnlessFeedback,
+	"required_with":    requiredWithFeedback,
+	"required_without": requiredWithoutFeedback,
+	"excluded_if":      excludedIfFeedback,
+	"excluded_unless":  excludedUnlessFeedback,
+	"excluded_with":    excludedWithFeedback,
+	"excluded_without": excludedWithoutFeedback,
 }
 
 type FeedbackSet struct {
@@ -49,6 +61,14 @@ func New() *FeedbackSet {
 
 // 将错误信息替换为中文
 func (self *FeedbackSet) Translate(f *validator.Feedback) string {
+	// 多选规则的每个选项分别翻译
+	if alternatives := f.Alternatives(); len(alternatives) > 0 {
+		buf := make([]string, len(alternatives))
+		for i, alternative := range alternatives {
+			buf[i] = self.Translate(alternative)
+		}
+		return strings.Join(buf, "或")
+	}
 	self.mutex.RLock()
 	if handler, ok := self.handlers[f.Validation.Flag]; ok {
 		self.mutex.RUnlock()
@@ -150,23 +170,23 @@ func passwordFeedback(f *validator.Feedback) string {
 }
 
 func eqfieldFeedback(f *validator.Feedback) string {
-	return fmt.Sprintf("该字段必须等于'%s'字段", f.Validation.Param)
+	return fmt.Sprintf("该字段必须等于'%s'字段", f.Validation.FieldName(f.Validation.Param))
 }
 
 func gtfieldFeedback(f *validator.Feedback) string {
-	return fmt.Sprintf("该字段必须大于'%s'字段", f.Validation.Param)
+	return fmt.Sprintf("该字段必须大于'%s'字段", f.Validation.FieldName(f.Validation.Param))
 }
 
 func gtefieldFeedback(f *validator.Feedback) string {
-	return fmt.Sprintf("该字段必须大于等于'%s'字段", f.Validation.Param)
+	return fmt.Sprintf("该字段必须大于等于'%s'字段", f.Validation.FieldName(f.Validation.Param))
 }
 
 func ltfieldFeedback(f *validator.Feedback) string {
-	return fmt.Sprintf("该字段必须小于'%s'字段", f.Validation.Param)
+	return fmt.Sprintf("该字段必须小于'%s'字段", f.Validation.FieldName(f.Validation.Param))
 }
 
 func ltefieldFeedback(f *validator.Feedback) string {
-	return fmt.Sprintf("该字段必须小于等于'%s'字段", f.Validation.Param)
+	return fmt.Sprintf("该字段必须小于等于'%s'字段", f.Validation.FieldName(f.Validation.Param))
 }
 
 func prefixFeedback(f *validator.Feedback) string {
@@ -176,3 +196,62 @@ func prefixFeedback(f *validator.Feedback) string {
 func suffixFeedback(f *validator.Feedback) string {
 	return fmt.Sprintf("该字段必须以'%s'结尾", f.Validation.Param)
 }
+
+// pairs formats the param "Type business Kind shop" as "Type为business且Kind为shop"
+func pairs(v *validator.Validation) string {
+	items, _ := validator.SplitParam(v.Param)
+	buf := make([]string, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		buf = append(buf, v.FieldName(items[i])+"为"+items[i+1])
+	}
+	return strings.Join(buf, "且")
+}
+
+// fields formats the param "Phone Email" as "Phone或Email"
+func fields(v *validator.Validation) string {
+	items, _ := validator.SplitParam(v.Param)
+	for i, item := range items {
+		items[i] = v.FieldName(item)
+	}
+	return strings.Join(items, "或")
+}
+
+func requiredIfFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s时该字段是必填的", pairs(f.Validation))
+}
+
+func requiredUnlessFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("除非%s，否则该字段是必填的", pairs(f.Validation))
+}
+
+func requiredWithFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s存在时该字段是必填的", fields(f.Validation))
+}
+
+func requiredWithoutFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s不存在时该字段是必填的", fields(f.Validation))
+}
+
+func excludedIfFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s时该字段必须为空", pairs(f.Validation))
+}
+
+func excludedUnlessFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("除非%s，否则该字段必须为空", pairs(f.Validation))
+}
+
+func excludedWithFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s存在时该字段必须为空", fields(f.Validation))
+}
+
+func excludedWithoutFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("当%s不存在时该字段必须为空", fields(f.Validation))
+}
+
+func notFeedback(f *validator.Feedback) string {
+	return fmt.Sprintf("该字段不能满足规则'%s'", f.Validation.Param)
+}
+
+func typeFeedback(f *validator.Feedback) string {
+	return "该字段的类型错误"
+}
@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Generated is implemented by the types whose Validate method is generated by cmd/validatorgen.
+// Only the direct calls of Validate skip reflection: the engine validates the tags of such types itself,
+// with its settings and loaded rules
+type Generated interface {
+	Validate() error
+	ValidatorGenerated()
+}
+
+// Failures collects the feedbacks of a generated Validate method. The generated code checks the rules
+// without reflection, the feedback of a failed rule is built by its builtin validator, so it's the
+// same as Engine.Validate reports with the default settings
+type Failures struct {
+	x   interface{}
+	err *ValidationError
+	// configErr is the first error of a validator that isn't a feedback
+	configErr error
+}
+
+// NewFailures returns the failures of x, a pointer to a struct
+func NewFailures(x interface{}) *Failures {
+	return &Failures{x: x}
+}
+
+// Fail reports that value, the field or an element of the field, fails the rule written as in the tag.
+// The feedback is built by the validator of the rule, a *ConfigError is reported if the value passes it
+func (self *Failures) Fail(path string, field string, value interface{}, rule string) {
+	flags, err := parseFlags(rule)
+	if err != nil || len(flags) != 1 {
+		self.fail(fmt.Errorf("invalid rule '%s'", rule))
+		return
+	}
+	structVal := reflect.ValueOf(self.x).Elem()
+	fieldTyp, _ := structVal.Type().FieldByName(field)
+	validator, ok := defaultValidators[flags[0].Name]
+	if !ok {
+		self.fail(&ConfigError{Field: field, Flag: flags[0].Name, Err: ErrUnregisteredValidator, s: fmt.Sprintf("Unregistered validator '%s'", flags[0].Name)})
+		return
+	}
+	v := &Validation{
+		StructField: fieldTyp,
+		Field:       reflect.ValueOf(value),
+		Struct:      structVal,
+		Flag:        flags[0].Name,
+		Param:       flags[0].Param,
+	}
+	switch err := validator(v).(type) {
+	case nil:
+		// the generated code disagrees with the validator, e.g. it's out of date
+		self.fail(&ConfigError{Field: path, Flag: flags[0].Name, s: fmt.Sprintf("Field '%s': the value passes '%s', regenerate the code", path, rule)})
+	case *Feedback:
+		self.errors().addFeedback(fieldTyp, field, path, err)
+	default:
+		self.fail(err)
+	}
+}
+
+// Merge adds the error returned by the Validate method of a nested struct, the paths are prefixed with prefix
+func (self *Failures) Merge(prefix string, err error) {
+	switch e := err.(type) {
+	case nil:
+	case *ValidationError:
+		for _, fieldError := range e.Detail {
+			for _, feedback := range fieldError.Feedbacks {
+				self.errors().addFeedback(fieldError.Field, fieldError.Name, joinPath(prefix, fieldError.Path), feedback)
+			}
+		}
+	default:
+		self.fail(err)
+	}
+}
+
+// Err returns the ValidationError, nil if no rule failed
+func (self *Failures) Err() error {
+	if self.configErr != nil {
+		return self.configErr
+	}
+	if self.err == nil {
+		return nil
+	}
+	return self.err
+}
+
+func (self *Failures) errors() *ValidationError {
+	if self.err == nil {
+		self.err = &ValidationError{Detail: make([]*FieldError, 0)}
+	}
+	return self.err
+}
+
+func (self *Failures) fail(err error) {
+	if self.configErr == nil {
+		self.configErr = err
+	}
+}
+
+// MatchString reports whether s passes the builtin string validator flag, e.g. email or prefix,
+// for the generated code. It panics if flag isn't a string validator
+func MatchString(flag string, param string, s string) bool {
+	match, ok := stringMatchers[flag]
+	if !ok {
+		panic(fmt.Sprintf("validator: '%s' is not a string validator", flag))
+	}
+	return match(param, s)
+}
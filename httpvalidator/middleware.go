@@ -0,0 +1,145 @@
+package httpvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/shaopson/validator"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type contextKey struct{}
+
+// Middleware decodes the JSON body of the requests into a struct and validates it
+type Middleware struct {
+	Engine *validator.Engine
+	// MaxBodySize limits the size of the request body, 0 means no limit
+	MaxBodySize int64
+	// ErrorHandler handles the errors of the validation other than a *validator.ValidationError,
+	// e.g. a *validator.ConfigError or the error of the request context, nil writes them with WriteFailure
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	translations map[string]validator.Translation
+	lock         sync.RWMutex
+}
+
+func New(engine *validator.Engine) *Middleware {
+	return &Middleware{
+		Engine:       engine,
+		translations: make(map[string]validator.Translation),
+	}
+}
+
+// RegisterTranslation registers the translation of a language, e.g. "zh" or "zh-TW",
+// it's picked by the Accept-Language header of the requests
+func (self *Middleware) RegisterTranslation(lang string, t validator.Translation) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.translations[strings.ToLower(lang)] = t
+}
+
+// Handler returns a handler that decodes the request body into a new value of the struct type of typ
+// and validates it. It responds 400 if the body can't be decoded and 422 if the validation fails,
+// otherwise it calls next with the value stored in the request context, see Value.
+// The other errors of the validation are given to ErrorHandler.
+// typ is a value or a pointer of the type
+func (self *Middleware) Handler(typ interface{}, next http.Handler) http.Handler {
+	t := reflect.TypeOf(typ)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic("httpvalidator: Handler only support 'Struct' type")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := r.Body
+		if self.MaxBodySize > 0 {
+			body = http.MaxBytesReader(w, body, self.MaxBodySize)
+		}
+		value := reflect.New(t).Interface()
+		if err := json.NewDecoder(body).Decode(value); err != nil {
+			p := NewProblem(http.StatusBadRequest, nil, nil)
+			p.Detail = err.Error()
+			p.Write(w)
+			return
+		}
+		if err := self.Engine.ValidateCtx(r.Context(), value); err != nil {
+			var e *validator.ValidationError
+			if errors.As(err, &e) {
+				WriteError(w, e, self.Translation(r))
+				return
+			}
+			if self.ErrorHandler != nil {
+				self.ErrorHandler(w, r, err)
+			} else {
+				WriteFailure(w, err)
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, value)))
+	})
+}
+
+// Translation returns the translation of the most preferred language of the Accept-Language
+// header of r, a language matches the translation of its primary tag, e.g. zh-CN matches zh.
+// It returns nil if none is registered, the feedbacks are in English
+func (self *Middleware) Translation(r *http.Request) validator.Translation {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	for _, lang := range acceptLanguages(r.Header.Get("Accept-Language")) {
+		if t, ok := self.translations[lang]; ok {
+			return t
+		}
+		if primary, _, ok := strings.Cut(lang, "-"); ok {
+			if t, ok := self.translations[primary]; ok {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// Value returns the validated value, a pointer of the struct type given to Middleware.Handler
+func Value(r *http.Request) interface{} {
+	return r.Context().Value(contextKey{})
+}
+
+// acceptLanguages returns the lowercase languages of an Accept-Language header by preference,
+// languages with q=0 are dropped
+func acceptLanguages(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, item := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(item, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		languages = append(languages, language{tag, q})
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+	result := make([]string, len(languages))
+	for i, l := range languages {
+		result[i] = l.tag
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+// Package httpvalidator renders validation errors as RFC 7807 problem details and
+// validates JSON request bodies in a http.Handler middleware
+package httpvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/shaopson/validator"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the status of the requests canceled by the client, as in nginx
+const StatusClientClosedRequest = 499
+
+// Problem is a RFC 7807 problem detail, with the invalid-params extension for validation errors
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is a feedback of the validation
+type InvalidParam struct {
+	// Name is the path of the field, e.g. address.zip
+	Name   string         `json:"name"`
+	Reason string         `json:"reason"`
+	Code   string         `json:"code,omitempty"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// NewProblem returns a problem of status with an invalid param per feedback of err,
+// the reasons are translated by t if it's not nil
+func NewProblem(status int, err *validator.ValidationError, t validator.Translation) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  statusText(status),
+		Status: status,
+	}
+	if err == nil {
+		return p
+	}
+	for _, e := range err.Detail {
+		for _, f := range e.Feedbacks {
+			reason := f.Error()
+			if t != nil {
+				reason = t.Translate(f)
+			}
+			p.InvalidParams = append(p.InvalidParams, InvalidParam{
+				Name:   e.Path,
+				Reason: reason,
+				Code:   f.Code,
+				Params: f.Params,
+			})
+		}
+	}
+	return p
+}
+
+// Write writes the problem as an application/problem+json response
+func (self *Problem) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(self.Status)
+	return json.NewEncoder(w).Encode(self)
+}
+
+// WriteError writes err as a 422 problem response, the reasons are translated by t if it's not nil
+func WriteError(w http.ResponseWriter, err *validator.ValidationError, t validator.Translation) error {
+	return NewProblem(http.StatusUnprocessableEntity, err, t).Write(w)
+}
+
+// WriteFailure writes err, an error of the validation that isn't a *validator.ValidationError, as a problem
+// response with err as the detail. The status is 499 if the request context is canceled, 503 if its deadline
+// is exceeded and 500 otherwise, e.g. for a *validator.ConfigError
+func WriteFailure(w http.ResponseWriter, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, context.Canceled):
+		status = StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusServiceUnavailable
+	}
+	p := NewProblem(status, nil, nil)
+	p.Detail = err.Error()
+	return p.Write(w)
+}
+
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
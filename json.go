@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// feedbackJSON is the JSON form of a feedback, errors are marshalled as an array of it
+type feedbackJSON struct {
+	Path    string         `json:"path"`
+	Field   string         `json:"field"`
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+func newFeedbackJSON(path string, field string, f *Feedback, t Translation) feedbackJSON {
+	message := f.s
+	if t != nil {
+		message = t.Translate(f)
+	}
+	return feedbackJSON{
+		Path:    path,
+		Field:   field,
+		Code:    f.Code,
+		Message: message,
+		Params:  f.Params,
+	}
+}
+
+// feedback rebuilds a feedback, the Validation only holds the field name and the flag taken from the code
+func (self *feedbackJSON) feedback() *Feedback {
+	flag, _, _ := strings.Cut(self.Code, ".")
+	v := &Validation{
+		StructField: reflect.StructField{Name: self.Field},
+		Flag:        flag,
+	}
+	if param, ok := self.Params["param"].(string); ok {
+		v.Param = param
+	}
+	return &Feedback{
+		Validation: v,
+		Code:       self.Code,
+		Params:     self.Params,
+		s:          self.Message,
+	}
+}
+
+// MarshalJSON encodes the feedback as {"path", "field", "code", "message", "params"}, the path is empty
+// as a feedback doesn't know where it belongs, marshal the FieldError or the ValidationError to get it
+func (self *Feedback) MarshalJSON() ([]byte, error) {
+	field := ""
+	if self.Validation != nil {
+		field = self.Validation.StructField.Name
+	}
+	return json.Marshal(newFeedbackJSON("", field, self, nil))
+}
+
+func (self *Feedback) UnmarshalJSON(data []byte) error {
+	var f feedbackJSON
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*self = *f.feedback()
+	return nil
+}
+
+// MarshalJSON encodes the error as an array with an element per feedback
+func (self *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(self.feedbacksJSON(nil))
+}
+
+// UnmarshalJSON rebuilds the error from the output of MarshalJSON,
+// numbers in the params are decoded as float64
+func (self *FieldError) UnmarshalJSON(data []byte) error {
+	var items []feedbackJSON
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*self = FieldError{}
+	for i := range items {
+		if i == 0 {
+			self.Field = reflect.StructField{Name: items[i].Field}
+			self.Name = items[i].Field
+			self.Path = items[i].Path
+		}
+		self.Feedbacks = append(self.Feedbacks, items[i].feedback())
+	}
+	return nil
+}
+
+func (self *FieldError) feedbacksJSON(t Translation) []feedbackJSON {
+	name := self.Name
+	if name == "" {
+		name = self.Field.Name
+	}
+	items := make([]feedbackJSON, len(self.Feedbacks))
+	for i, f := range self.Feedbacks {
+		items[i] = newFeedbackJSON(self.Path, name, f, t)
+	}
+	return items
+}
+
+// MarshalJSON encodes the error as an array with an element per feedback, in the order of Detail.
+// The messages are translated if a translation is set
+func (self *ValidationError) MarshalJSON() ([]byte, error) {
+	items := make([]feedbackJSON, 0, len(self.Detail))
+	for _, e := range self.Detail {
+		items = append(items, e.feedbacksJSON(self.translation)...)
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON rebuilds the error from the output of MarshalJSON, the feedbacks
+// are grouped by path. Numbers in the params are decoded as float64
+func (self *ValidationError) UnmarshalJSON(data []byte) error {
+	var items []feedbackJSON
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*self = ValidationError{}
+	for i := range items {
+		item := &items[i]
+		self.addFeedback(reflect.StructField{Name: item.Field}, item.Field, item.Path, item.feedback())
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// wildcard matches every element of a slice or every key of a map in the keys of ValidateMap rules
+const wildcard = "*"
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// typeCode is the code of the feedbacks of ValidateMap for the values of a type the rules don't support
+const typeCode = "type"
+
+// presenceFlags are the validators run on the absent and null values of ValidateMap
+var presenceFlags = map[string]bool{
+	"required":         true,
+	"required_if":      true,
+	"required_unless":  true,
+	"required_with":    true,
+	"required_without": true,
+}
+
+// mapValue is a value of the data of ValidateMap matched by the key of a rule
+type mapValue struct {
+	path string
+	name string
+	// value is invalid if the key is absent
+	value reflect.Value
+	// parent is the map holding the value, for the *_field and conditional validators
+	parent reflect.Value
+}
+
+// ValidateMap validates untyped data, e.g. decoded JSON, against rules written in the tag syntax.
+// The rules are keyed by dotted paths, '*' matches every element of a slice or every key of a map:
+//
+//	rules := map[string]string{
+//		"name":          "required,len:2-20",
+//		"address.city":  "required",
+//		"items.*.price": "required,gt:0",
+//	}
+//
+// The *_field and conditional validators name the keys of the same map. Absent and null values are
+// only checked by required and the required_* validators. A value of a type the builtin validators of
+// its rule don't support, e.g. a number for email, fails with the code "type" rather than a *ConfigError.
+// The rules are run in the order of their keys and the errors are reported as by Validate, paths look
+// like items[0].price
+func (self *Engine) ValidateMap(data map[string]any, rules map[string]string) error {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	self.lock.RLock()
+	structError := &ValidationError{
+		Detail: make([]*FieldError, 0),
+		limits: self.limits,
+	}
+	self.lock.RUnlock()
+	root := reflect.ValueOf(data)
+	for _, key := range keys {
+		var values []mapValue
+		collectMapValues(root, strings.Split(key, "."), "", "", reflect.Value{}, &values)
+		for _, mv := range values {
+			if structError.stop {
+				structError.Truncated = true
+				return structError
+			}
+			if err := self.validateMapValue(mv, rules[key], structError); err != nil {
+				return err
+			}
+		}
+	}
+	if len(structError.Detail) > 0 {
+		return structError
+	}
+	return nil
+}
+
+// collectMapValues walks value along the segments of a rule key and collects the matched values
+func collectMapValues(value reflect.Value, segments []string, path string, name string, parent reflect.Value, values *[]mapValue) {
+	value = elemValue(value)
+	if len(segments) == 0 {
+		*values = append(*values, mapValue{path: path, name: name, value: value, parent: parent})
+		return
+	}
+	segment := segments[0]
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if segment == wildcard {
+			for _, key := range sortedMapKeys(value) {
+				collectMapValues(value.MapIndex(key), segments[1:], fmt.Sprintf("%s[%s]", path, key), name, value, values)
+			}
+			return
+		}
+		elem := value.MapIndex(reflect.ValueOf(segment).Convert(value.Type().Key()))
+		collectMapValues(elem, segments[1:], joinPath(path, segment), segment, value, values)
+		return
+	case reflect.Slice, reflect.Array:
+		if segment == wildcard {
+			for i := 0; i < value.Len(); i++ {
+				collectMapValues(value.Index(i), segments[1:], fmt.Sprintf("%s[%d]", path, i), name, parent, values)
+			}
+			return
+		}
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 {
+			elem := reflect.Value{}
+			if i < value.Len() {
+				elem = value.Index(i)
+			}
+			collectMapValues(elem, segments[1:], fmt.Sprintf("%s[%d]", path, i), name, parent, values)
+			return
+		}
+	}
+	// the rest of the key is absent, unless it matches the elements of the absent value
+	for _, s := range segments {
+		if s == wildcard {
+			return
+		}
+		path = joinPath(path, s)
+	}
+	*values = append(*values, mapValue{path: path, name: segments[len(segments)-1], parent: parent})
+}
+
+func (self *Engine) validateMapValue(mv mapValue, tag string, structError *ValidationError) error {
+	field := mv.value
+	absent := !field.IsValid() || (field.Kind() == reflect.Interface || field.Kind() == reflect.Pointer ||
+		field.Kind() == reflect.Map || field.Kind() == reflect.Slice) && field.IsNil()
+	if absent {
+		field = reflect.Zero(anyType)
+	}
+	vp, err := self.varPlan(varKey{typ: field.Type(), tag: tag})
+	if err != nil {
+		return fmt.Errorf("Field '%s': %w", mv.path, err)
+	}
+	plan := *vp
+	plan.name = mv.name
+	plan.rules = make([]*rule, 0, len(vp.rules))
+	for _, rule := range vp.rules {
+		if absent && !presenceFlags[rule.flag] {
+			continue
+		}
+		plan.rules = append(plan.rules, rule)
+	}
+	if absent {
+		plan.dive = false
+	}
+	var structVal reflect.Value
+	plan.rules, structVal = bindSiblings(plan.rules, mv.parent)
+	fieldTyp := reflect.StructField{Name: mv.name, Type: field.Type()}
+	if r := mismatchedRule(plan.rules, field.Type()); r != nil && !(plan.omitEmpty && field.IsZero()) {
+		v := newValidation(structError.ctx, r, fieldTyp, field, structVal)
+		v.Flag = typeCode
+		v.Param = ""
+		typ := jsonType(field.Type())
+		feedback := v.ErrorCode(typeCode, map[string]any{"type": typ, "rule": r.text}, fmt.Sprintf("invalid type '%s'", typ))
+		structError.addFeedback(fieldTyp, mv.name, mv.path, feedback.(*Feedback))
+		if structError.limits.failFast {
+			structError.stop = true
+		}
+		return nil
+	}
+	return self.validateValue(fieldTyp, field, structVal, &plan, mv.path, structError)
+}
+
+// mismatchedRule returns the first rule of a builtin validator that doesn't support typ, the alternatives
+// of the OR groups included, nil if every rule supports it
+func mismatchedRule(rules []*rule, typ reflect.Type) *rule {
+	for _, r := range rules {
+		if len(r.alternatives) > 0 {
+			if mismatched := mismatchedRule(r.alternatives, typ); mismatched != nil {
+				return mismatched
+			}
+			continue
+		}
+		check, ok := typeChecks[r.flag]
+		if ok && isBuiltin(r.flag, r.validator) && !check(typ) {
+			return r
+		}
+	}
+	return nil
+}
+
+// jsonType names typ as the JSON types, for the feedbacks of ValidateMap
+func jsonType(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	}
+	switch typeClass(typ) {
+	case "int", "uint", "float":
+		return "number"
+	}
+	return typ.String()
+}
+
+// bindSiblings points the *_field and conditional validators to the keys of parent they name.
+// The values of the keys are held by a struct built for the rules, the rules are copied
+func bindSiblings(rules []*rule, parent reflect.Value) ([]*rule, reflect.Value) {
+	var names []string
+	index := make(map[string]int)
+	var bind func(rules []*rule) []*rule
+	field := func(name string) fieldIndex {
+		i, ok := index[name]
+		if !ok {
+			i = len(names)
+			index[name] = i
+			names = append(names, name)
+		}
+		return fieldIndex{i}
+	}
+	bind = func(rules []*rule) []*rule {
+		bound := make([]*rule, len(rules))
+		for i, r := range rules {
+			bound[i] = r
+			pairs, conditional := isCondition(r.flag)
+			if !isCrossField(r.flag) && !conditional && len(r.alternatives) == 0 {
+				continue
+			}
+			copied := *r
+			copied.alternatives = bind(r.alternatives)
+			if isCrossField(r.flag) {
+				copied.arg = field(r.param)
+			} else if conditional {
+				copied.arg = nil
+				if cond, err := splitCondition(r.param, pairs); err == nil {
+					for j, name := range cond.names {
+						cond.fields[j] = field(name)
+					}
+					copied.arg = cond
+				}
+			}
+			bound[i] = &copied
+		}
+		return bound
+	}
+	rules = bind(rules)
+	if len(names) == 0 {
+		return rules, reflect.Value{}
+	}
+	fields := make([]reflect.StructField, len(names))
+	values := make([]reflect.Value, len(names))
+	for i, name := range names {
+		value := reflect.Value{}
+		if parent.IsValid() {
+			value = elemValue(parent.MapIndex(reflect.ValueOf(name).Convert(parent.Type().Key())))
+		}
+		if !value.IsValid() {
+			value = reflect.Zero(anyType)
+		}
+		fields[i] = reflect.StructField{Name: "F" + strconv.Itoa(i), Type: value.Type()}
+		values[i] = value
+	}
+	structVal := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, value := range values {
+		structVal.Field(i).Set(value)
+	}
+	return rules, structVal
+}
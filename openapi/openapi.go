@@ -0,0 +1,80 @@
+// Package openapi generates the components.schemas of OpenAPI 3.1 documents from the rules of the
+// request types registered on an Engine, their tags and the rules of LoadRules and RegisterRules,
+// so the API docs match the rules the engine enforces.
+// The schemas are JSON Schema, see the schema package, the rules without a schema equivalent are
+// exported as x- extensions, e.g. "x-eq_field": "password" or "x-password": 2
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/schema"
+)
+
+// SchemasRef is the prefix of the references to the schemas of the components
+const SchemasRef = "#/components/schemas/"
+
+// Components is the components object of an OpenAPI document, only the schemas are generated
+type Components struct {
+	Schemas schema.Schema `json:"schemas"`
+}
+
+// Generator generates the components of the types registered on the engine by Engine.MustRegisterType
+type Generator struct {
+	Engine *validator.Engine
+	// Schema generates the schemas with the rules of the engine given to New, register the Funcs
+	// of the custom validators on it
+	Schema *schema.Generator
+}
+
+func New(engine *validator.Engine) *Generator {
+	generator := schema.New()
+	generator.SetExtensions(true)
+	generator.SetFlagsFunc(engine.FieldFlags)
+	return &Generator{
+		Engine: engine,
+		Schema: generator,
+	}
+}
+
+// Components returns the schemas of the registered types and of the struct types nested in them,
+// with the rules the engine applies to their fields
+func (self *Generator) Components() (*Components, error) {
+	registered := self.Engine.RegisteredTypes()
+	types := make([]interface{}, len(registered))
+	for i, typ := range registered {
+		types[i] = typ
+	}
+	schemas, err := self.Schema.Definitions(SchemasRef, types...)
+	if err != nil {
+		return nil, err
+	}
+	return &Components{Schemas: schemas}, nil
+}
+
+// JSON renders the components as {"components": {"schemas": ...}}, to be merged into a document
+func (self *Components) JSON() ([]byte, error) {
+	return json.MarshalIndent(map[string]interface{}{"components": self}, "", "  ")
+}
+
+// YAML renders the components as YAML, see JSON. The document is written without a YAML library
+// in block style only: mappings with sorted keys, sequences of "- " items and empty ones as {} or [].
+// Strings are plain if they start with a letter, '_' or '$' and hold only letters, digits and
+// "_$ ./-", don't end with a space and aren't read as booleans or null, e.g. "yes".
+// The others are double-quoted as in JSON, numbers are written as in JSON
+func (self *Components) YAML() ([]byte, error) {
+	data, err := json.Marshal(map[string]interface{}{"components": self})
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	writeYAML(buf, doc, 0, "")
+	return buf.Bytes(), nil
+}
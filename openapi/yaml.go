@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// plainRegx matches the strings written without quotes
+var plainRegx = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$ ./-]*$`)
+
+// reserved are the plain scalars YAML reads as other types than string
+var reserved = map[string]bool{
+	"true": true, "false": true, "null": true, "yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+}
+
+// writeYAML writes v, decoded from JSON with UseNumber, as a block node, see Components.YAML for
+// the subset of YAML it writes. first is written before the first line instead of the indentation,
+// e.g. the "- " of a sequence item
+func writeYAML(buf *bytes.Buffer, v interface{}, indent int, first string) {
+	pad := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i == 0 {
+				buf.WriteString(first)
+			} else {
+				buf.WriteString(pad)
+			}
+			buf.WriteString(scalar(k) + ":")
+			writeChild(buf, v[k], indent+1)
+		}
+	case []interface{}:
+		for i, e := range v {
+			if i == 0 {
+				buf.WriteString(first)
+			} else {
+				buf.WriteString(pad)
+			}
+			buf.WriteString("-")
+			if isBlock(e) {
+				writeYAML(buf, e, indent+1, " ")
+			} else {
+				buf.WriteString(" " + scalar(e) + "\n")
+			}
+		}
+	}
+}
+
+func writeChild(buf *bytes.Buffer, v interface{}, indent int) {
+	if !isBlock(v) {
+		buf.WriteString(" " + scalar(v) + "\n")
+		return
+	}
+	buf.WriteString("\n")
+	pad := strings.Repeat("  ", indent)
+	writeYAML(buf, v, indent, pad)
+}
+
+// isBlock reports whether v is a non-empty mapping or sequence
+func isBlock(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// scalar writes v as a flow scalar, the strings are plain if plainRegx matches them and they can't
+// be read as another type, they're double-quoted as in JSON otherwise
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	case string:
+		if plainRegx.MatchString(v) && !strings.HasSuffix(v, " ") && !reserved[strings.ToLower(v)] {
+			return v
+		}
+		// a JSON string is a double-quoted YAML scalar
+		buf := &bytes.Buffer{}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(v)
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+	return ""
+}
@@ -0,0 +1,456 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structPlan is the compiled form of the tags of a struct type, it is built
+// once per type and cached by the Engine
+type structPlan struct {
+	fields []*fieldPlan
+	// struct level validation, see Validatable, StructLevelValidatable and Engine.RegisterStructValidator
+	validate        bool
+	validateWith    bool
+	structValidator StructValidator
+	err             error
+}
+
+type fieldPlan struct {
+	index int
+	field reflect.StructField
+	// name is the name of the field in paths, see Engine.RegisterTagNameFunc
+	name string
+	// flatten is set on embedded structs without a custom name, whose fields keep the path of the outer struct
+	flatten bool
+	// value is nil for the fields without tag that are only descended into
+	value *valuePlan
+}
+
+// valuePlan is the compiled form of the flags applied to a field or to the elements of a field
+type valuePlan struct {
+	name      string
+	omitEmpty bool
+	rules     []*rule
+	dive      bool
+	keys      *valuePlan
+	elem      *valuePlan
+	// flatten is set on the plan of an embedded struct field, whose fields keep the path of the outer struct
+	flatten bool
+}
+
+type rule struct {
+	flag      string
+	param     string
+	validator Validator
+	handler   FeedbackHandler
+	// arg is the param parsed ahead of time for the type of the field, nil if it can't be parsed
+	arg      interface{}
+	nameFunc TagNameFunc
+	// negate inverts the result of the validator, text is the rule as written in the tag
+	negate bool
+	text   string
+	// expensive runs the validator concurrently with the other expensive ones, see Engine.SetConcurrency
+	expensive bool
+	// alternatives of an OR group, the rule passes if any of them passes
+	alternatives []*rule
+}
+
+// fieldIndex is the resolved index of the target field of the *_field validators
+type fieldIndex []int
+
+type paramParser func(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error)
+
+// paramParsers pre-parse the params of the builtin validators when a plan is compiled
+var paramParsers = map[string]paramParser{
+	"len":       parseLenArg,
+	"eq":        parseValueArg,
+	"gt":        parseValueArg,
+	"gte":       parseValueArg,
+	"lt":        parseValueArg,
+	"lte":       parseValueArg,
+	"eq_field":  parseFieldArg,
+	"lt_field":  parseFieldArg,
+	"lte_field": parseFieldArg,
+	"gt_field":  parseFieldArg,
+	"gte_field": parseFieldArg,
+	"ip":        parseIPArg,
+	"password":  parsePasswordArg,
+
+	"required_if":      parsePairsArg,
+	"required_unless":  parsePairsArg,
+	"required_with":    parseFieldsArg,
+	"required_without": parseFieldsArg,
+	"excluded_if":      parsePairsArg,
+	"excluded_unless":  parsePairsArg,
+	"excluded_with":    parseFieldsArg,
+	"excluded_without": parseFieldsArg,
+}
+
+// structPlan returns the cached plan of typ, compiling it on first use
+func (self *Engine) structPlan(typ reflect.Type) (*structPlan, error) {
+	self.lock.RLock()
+	plan, ok := self.plans[typ]
+	self.lock.RUnlock()
+	if ok {
+		return plan, plan.err
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if plan, ok = self.plans[typ]; !ok {
+		plan = self.compileStruct(typ)
+		self.plans[typ] = plan
+	}
+	return plan, plan.err
+}
+
+// resetPlans drops the compiled plans, the caller must hold the write lock
+func (self *Engine) resetPlans() {
+	self.plans = make(map[reflect.Type]*structPlan)
+	self.vars = make(map[varKey]*varPlan)
+}
+
+func (self *Engine) compileStruct(typ reflect.Type) *structPlan {
+	ptrTyp := reflect.PointerTo(typ)
+	plan := &structPlan{
+		fields:          make([]*fieldPlan, 0, typ.NumField()),
+		validate:        ptrTyp.Implements(validatableType),
+		validateWith:    ptrTyp.Implements(structLevelValidatableType),
+		structValidator: self.structValidators[typ],
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		fieldTyp := typ.Field(i)
+		// the exported fields of an unexported embedded struct are still promoted
+		if !fieldTyp.IsExported() && !fieldTyp.Anonymous {
+			continue
+		}
+		flags, ok, skip, err := self.fieldFlags(self.rules, typ, fieldTyp)
+		if skip {
+			continue
+		}
+		if err != nil {
+			plan.err = err
+			return plan
+		}
+		name, custom := self.fieldName(fieldTyp)
+		fp := &fieldPlan{
+			index:   i,
+			field:   fieldTyp,
+			name:    name,
+			flatten: fieldTyp.Anonymous && !custom,
+		}
+		if ok {
+			vp, err := self.compileValue(name, fieldTyp.Type, typ, flags)
+			if err != nil {
+				plan.err = err
+				return plan
+			}
+			vp.flatten = fp.flatten
+			fp.value = vp
+		} else if !isStructType(fieldTyp.Type) {
+			continue
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+	return plan
+}
+
+// compileValue compiles the flags applied to a value of typ, typ is nil when
+// the type is only known at validation time, e.g. the elements of []any
+func (self *Engine) compileValue(name string, typ reflect.Type, structTyp reflect.Type, flags []Flag) (*valuePlan, error) {
+	fieldFlags, elemFlags, dive := splitDive(flags)
+	vp := &valuePlan{
+		name:      name,
+		omitEmpty: hasFlag(fieldFlags, omitemptyFlag),
+		rules:     make([]*rule, 0, len(fieldFlags)),
+		dive:      dive,
+	}
+	for _, flag := range fieldFlags {
+		if flag.Name == omitemptyFlag {
+			continue
+		}
+		rule, err := self.compileRule(flag, typ, structTyp)
+		if err != nil {
+			return nil, err
+		}
+		vp.rules = append(vp.rules, rule)
+	}
+	if !dive {
+		return vp, nil
+	}
+	keysFlags, elemFlags, keys, err := splitKeys(elemFlags)
+	if err != nil {
+		return nil, fmt.Errorf("Field '%s': %w", name, err)
+	}
+	var keyTyp, elemTyp reflect.Type
+	if typ != nil {
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		switch typ.Kind() {
+		case reflect.Map:
+			keyTyp = typ.Key()
+			elemTyp = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			if keys {
+				return nil, &ConfigError{Field: name, Flag: keysFlag, Err: ErrUnsupportedType, s: fmt.Sprintf("Field '%s': '%s' only support map type", name, keysFlag)}
+			}
+			elemTyp = typ.Elem()
+		case reflect.Interface:
+		default:
+			return nil, &ConfigError{Field: name, Flag: diveFlag, Err: ErrUnsupportedType, s: fmt.Sprintf("Field '%s': cannot dive into type '%s'", name, typ)}
+		}
+	}
+	if keys {
+		if vp.keys, err = self.compileValue(name, elemType(keyTyp), structTyp, keysFlags); err != nil {
+			return nil, err
+		}
+	}
+	if vp.elem, err = self.compileValue(name, elemType(elemTyp), structTyp, elemFlags); err != nil {
+		return nil, err
+	}
+	return vp, nil
+}
+
+func (self *Engine) compileRule(flag Flag, typ reflect.Type, structTyp reflect.Type) (*rule, error) {
+	r := &rule{
+		flag:     flag.Name,
+		param:    flag.Param,
+		negate:   flag.Negate,
+		text:     flag.String(),
+		nameFunc: self.tagNameFunc,
+	}
+	if len(flag.Alternatives) > 0 {
+		for _, alternative := range flag.Alternatives {
+			ar, err := self.compileRule(alternative, typ, structTyp)
+			if err != nil {
+				return nil, err
+			}
+			r.alternatives = append(r.alternatives, ar)
+		}
+		r.handler = self.FeedbackHandlers[orFlag]
+		return r, nil
+	}
+	validator, ok := self.Validators[flag.Name]
+	if !ok {
+		return nil, &ConfigError{Flag: flag.Name, Err: ErrUnregisteredValidator, s: fmt.Sprintf("Unregistered validator '%s'", flag.Name)}
+	}
+	r.validator = validator
+	r.expensive = self.expensive[flag.Name]
+	r.arg = parseArg(flag, typ, structTyp)
+	if flag.Negate {
+		// the negated rule is shown without the leading '!'
+		r.text = strings.TrimPrefix(r.text, "!")
+		r.handler = self.FeedbackHandlers[notFlag]
+	} else {
+		r.handler = self.FeedbackHandlers[flag.Name]
+	}
+	return r, nil
+}
+
+// fieldName returns the name of field in paths, custom reports whether it comes from the TagNameFunc
+func (self *Engine) fieldName(field reflect.StructField) (name string, custom bool) {
+	if self.tagNameFunc != nil {
+		if name = self.tagNameFunc(field); name != "" {
+			return name, true
+		}
+	}
+	return field.Name, false
+}
+
+// elemType returns nil for interface types, the dynamic type of the element is used at validation time
+func elemType(typ reflect.Type) reflect.Type {
+	if typ == nil || typ.Kind() == reflect.Interface {
+		return nil
+	}
+	return typ
+}
+
+// isStructType reports whether a value of typ may be descended into, see nestedStruct
+func isStructType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && !typ.ConvertibleTo(timeType)
+}
+
+// parseArg pre-parses the param of a builtin validator. Params that can't be parsed
+// are left to the validator, which reports the error at validation time
+func parseArg(flag Flag, typ reflect.Type, structTyp reflect.Type) interface{} {
+	parser, ok := paramParsers[flag.Name]
+	if !ok {
+		return nil
+	}
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	arg, err := parser(flag.Param, typ, structTyp)
+	if err != nil {
+		return nil
+	}
+	return arg
+}
+
+func parseLenArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	return parseLenParam(param)
+}
+
+func parseValueArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	if typ == nil {
+		return nil, nil
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(param, 0, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(param, 0, 64)
+	case reflect.Float32:
+		return strconv.ParseFloat(param, 32)
+	case reflect.Float64:
+		return strconv.ParseFloat(param, 64)
+	case reflect.Struct:
+		if typ.ConvertibleTo(timeType) {
+			return parseTimeParam(param)
+		}
+	}
+	return nil, nil
+}
+
+func parseFieldArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	if structTyp == nil {
+		return nil, fmt.Errorf("no struct to find field '%s'", param)
+	}
+	field, ok := structTyp.FieldByName(param)
+	if !ok {
+		return nil, fmt.Errorf("field '%s' not found", param)
+	}
+	return fieldIndex(field.Index), nil
+}
+
+func parseIPArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	switch param {
+	case "", "v4", "v6":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("invalid param '%s'", param)
+}
+
+func parsePasswordArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	switch param {
+	case "", "1", "2", "3":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("invalid param '%s'", param)
+}
+
+func parsePairsArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	return parseCondition(param, structTyp, true)
+}
+
+func parseFieldsArg(param string, typ reflect.Type, structTyp reflect.Type) (interface{}, error) {
+	return parseCondition(param, structTyp, false)
+}
+
+func parseLenParam(param string) ([]int, error) {
+	if param == "" {
+		return nil, fmt.Errorf("missing param")
+	}
+	params := strings.SplitN(param, "-", 2)
+	args := make([]int, len(params))
+	for i, p := range params {
+		arg, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid param '%s'", param)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// parseTimeParam parses a param in the format 2006-01-02 15:04:05 or 2006-01-02
+func parseTimeParam(param string) (time.Time, error) {
+	var t time.Time
+	var err error
+	if strings.Contains(param, ":") {
+		if t, err = time.Parse("2006-01-02 15:04:05", param); err != nil {
+			return t, fmt.Errorf("parse param failure:%s", err)
+		}
+	} else if strings.Contains(param, "-") {
+		if t, err = time.Parse("2006-01-02", param); err != nil {
+			return t, fmt.Errorf("parse param failure:%s", err)
+		}
+	} else {
+		return t, fmt.Errorf("invalid param '%s'", param)
+	}
+	return t, nil
+}
+
+func (self *Validation) lenParam() ([]int, error) {
+	if arg, ok := self.arg.([]int); ok {
+		return arg, nil
+	}
+	return parseLenParam(self.Param)
+}
+
+func (self *Validation) intParam() (int64, error) {
+	if arg, ok := self.arg.(int64); ok {
+		return arg, nil
+	}
+	return strconv.ParseInt(self.Param, 0, 64)
+}
+
+func (self *Validation) uintParam() (uint64, error) {
+	if arg, ok := self.arg.(uint64); ok {
+		return arg, nil
+	}
+	return strconv.ParseUint(self.Param, 0, 64)
+}
+
+func (self *Validation) floatParam(bitSize int) (float64, error) {
+	if arg, ok := self.arg.(float64); ok {
+		return arg, nil
+	}
+	return strconv.ParseFloat(self.Param, bitSize)
+}
+
+func (self *Validation) timeParam() (time.Time, error) {
+	if arg, ok := self.arg.(time.Time); ok {
+		return arg, nil
+	}
+	return parseTimeParam(self.Param)
+}
+
+// valueParam returns the param of the comparison validators typed after the field,
+// falls back to the raw param
+func (self *Validation) valueParam() interface{} {
+	if self.arg != nil {
+		return self.arg
+	}
+	if self.Field.IsValid() {
+		typ := self.Field.Type()
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if arg, err := parseValueArg(self.Param, typ, nil); err == nil && arg != nil {
+			return arg
+		}
+	}
+	return self.Param
+}
+
+// targetField returns the field named by the param of the *_field validators
+func (self *Validation) targetField() (reflect.Value, error) {
+	if index, ok := self.arg.(fieldIndex); ok {
+		return self.Struct.FieldByIndex(index), nil
+	}
+	if !self.Struct.IsValid() {
+		return reflect.Value{}, fmt.Errorf("param error: field '%s' not found", self.Param)
+	}
+	if _, ok := self.Struct.Type().FieldByName(self.Param); !ok {
+		return reflect.Value{}, fmt.Errorf("param error: field '%s' not found", self.Param)
+	}
+	return self.Struct.FieldByName(self.Param), nil
+}
@@ -0,0 +1,335 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mergePrefix marks the rules merged with the tag rather than replacing it
+const mergePrefix = "+"
+
+// fieldRules are the rules of a field loaded by LoadRules
+type fieldRules struct {
+	flags []Flag
+	merge bool
+}
+
+// LoadRules attaches rules to the fields of the types registered by MustRegisterType, from a JSON or
+// YAML document keyed by type name and field path. The rules use the tag syntax and replace the tag
+// of the field, unless they start with '+' and are merged with it: the validators of the tag are
+// replaced by the ones of the same name and the others, OR groups included, are appended.
+//
+//	User:
+//	  Name: "+len:2-50"        # keeps required, changes the length
+//	  Address.City: required   # the field of the nested type, wherever it's nested
+//
+// Types are named as reflect.Type.String, e.g. models.User, or by their name if it's unique.
+// A document starting with '{' is JSON, otherwise it's read as the subset of YAML above: a line
+// 'Type:' without indentation per type, followed by an indented line 'path: rules' per field.
+// The values are plain, 'single' or "double" quoted scalars on one line, a '#' after a space starts
+// a comment and '---' lines are skipped. Anchors, tags, flow and block collections and multi-line
+// scalars are rejected, so the rules starting with '!' must be quoted, e.g. '!eq:0'.
+// The rules are checked as Check does before they are used, nothing is loaded if any is wrong.
+// Later documents replace the rules of the same fields
+func (self *Engine) LoadRules(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeRules(data)
+	if err != nil {
+		return &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Invalid rules: %s", err)}
+	}
+	registered := self.RegisteredTypes()
+	loaded := make(map[reflect.Type]map[string]*fieldRules)
+	var types []interface{}
+	var errs []error
+	for _, typeName := range sortedKeys(doc) {
+		typ, err := lookupType(registered, typeName)
+		if err != nil {
+			errs = append(errs, &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Rules '%s': %s", typeName, err)})
+			continue
+		}
+		types = append(types, reflect.New(typ).Interface())
+		for _, path := range sortedKeys(doc[typeName]) {
+			fieldPath := typeName + "." + path
+			owner, field, err := resolveFieldPath(typ, path)
+			if err != nil {
+				errs = append(errs, &ConfigError{Field: fieldPath, Err: ErrInvalidRules, s: fmt.Sprintf("Rules '%s': %s", fieldPath, err)})
+				continue
+			}
+			rule := strings.TrimSpace(doc[typeName][path])
+			fr := &fieldRules{merge: strings.HasPrefix(rule, mergePrefix)}
+			if fr.flags, err = parseFlags(strings.TrimPrefix(rule, mergePrefix)); err != nil {
+				errs = append(errs, fmt.Errorf("Rules '%s': %w", fieldPath, err))
+				continue
+			}
+			if loaded[owner] == nil {
+				loaded[owner] = make(map[string]*fieldRules)
+			}
+			loaded[owner][field.Name] = fr
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return self.setRules(loaded, types)
+}
+
+// setRules checks the types with the loaded rules added to the current ones and publishes them if
+// the check passes. The lock is held throughout, so the rules loaded concurrently don't drop each other
+func (self *Engine) setRules(loaded map[reflect.Type]map[string]*fieldRules, types []interface{}) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	rules := make(map[reflect.Type]map[string]*fieldRules, len(self.rules)+len(loaded))
+	for typ, fields := range self.rules {
+		rules[typ] = fields
+	}
+	for typ, fields := range loaded {
+		merged := make(map[string]*fieldRules, len(rules[typ])+len(fields))
+		for name, fr := range rules[typ] {
+			merged[name] = fr
+		}
+		for name, fr := range fields {
+			merged[name] = fr
+		}
+		rules[typ] = merged
+	}
+	if err := self.check(rules, types); err != nil {
+		return err
+	}
+	self.rules = rules
+	self.resetPlans()
+	return nil
+}
+
+// FieldFlags returns the flags the engine applies to a field of the struct type structTyp, from its tag and
+// the rules of LoadRules and RegisterRules, e.g. to export the rules. ok is false if the field has none
+func (self *Engine) FieldFlags(structTyp reflect.Type, field reflect.StructField) (flags []Flag, ok bool, err error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	flags, ok, _, err = self.fieldFlags(self.rules, structTyp, field)
+	return flags, ok, err
+}
+
+// fieldFlags returns the flags of a struct field, from its tag and the loaded rules, the caller must hold the lock.
+// ok is false if the field has no rules, skip is set if it's tagged '-' without loaded rules.
+// The tag of an unexported field is ignored
+func (self *Engine) fieldFlags(rules map[reflect.Type]map[string]*fieldRules, structTyp reflect.Type, field reflect.StructField) (flags []Flag, ok bool, skip bool, err error) {
+	tag, tagged := field.Tag.Lookup(self.tagName)
+	fr, loaded := rules[structTyp][field.Name]
+	if tag == skipFlag {
+		if !loaded {
+			return nil, false, true, nil
+		}
+		tagged = false
+	}
+	if !field.IsExported() || !tagged && !loaded {
+		return nil, false, false, nil
+	}
+	if loaded && (!fr.merge || !tagged) {
+		return fr.flags, true, false, nil
+	}
+	if flags, err = parseFlags(tag); err != nil || !loaded {
+		return flags, true, false, err
+	}
+	return mergeFlags(flags, fr.flags), true, false, nil
+}
+
+// mergeFlags merges the loaded flags with the flags of the tag. The validators before dive are replaced
+// by the ones of the same name or appended, the OR groups are appended unless the tag has the same group.
+// The flags after dive replace the ones of the tag
+func mergeFlags(tagFlags []Flag, flags []Flag) []Flag {
+	tagField, tagElem, tagDive := splitDive(tagFlags)
+	field, elem, dive := splitDive(flags)
+	merged := append([]Flag(nil), tagField...)
+	for _, flag := range field {
+		replaced := false
+		for i, tagFlag := range merged {
+			if sameFlag(tagFlag, flag) {
+				merged[i] = flag
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, flag)
+		}
+	}
+	if dive {
+		merged = append(merged, Flag{Name: diveFlag})
+		return append(merged, elem...)
+	}
+	if tagDive {
+		merged = append(merged, Flag{Name: diveFlag})
+		merged = append(merged, tagElem...)
+	}
+	return merged
+}
+
+// sameFlag reports whether the loaded flag replaces the flag of the tag, the OR groups are all named
+// after orFlag and only replace the same group
+func sameFlag(tagFlag Flag, flag Flag) bool {
+	if len(tagFlag.Alternatives) > 0 || len(flag.Alternatives) > 0 {
+		return tagFlag.String() == flag.String()
+	}
+	return tagFlag.Name != "" && tagFlag.Name == flag.Name
+}
+
+// lookupType finds the registered type named name, by its full name or by its name if it's unique
+func lookupType(types []reflect.Type, name string) (reflect.Type, error) {
+	var found reflect.Type
+	for _, typ := range types {
+		if typ.String() == name {
+			return typ, nil
+		}
+		if typ.Name() == name {
+			if found != nil {
+				return nil, fmt.Errorf("ambiguous type name, use the package name")
+			}
+			found = typ
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("type not registered, see MustRegisterType")
+	}
+	return found, nil
+}
+
+// resolveFieldPath returns the field at the dotted path of Go field names and the struct type declaring it
+func resolveFieldPath(typ reflect.Type, path string) (reflect.Type, reflect.StructField, error) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		field, ok := typ.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return nil, reflect.StructField{}, fmt.Errorf("field '%s' not found", name)
+		}
+		if len(field.Index) > 1 {
+			// a promoted field is declared by the embedded struct
+			typ = derefType(typ.FieldByIndex(field.Index[:len(field.Index)-1]).Type)
+		}
+		if i == len(names)-1 {
+			return typ, field, nil
+		}
+		if !isStructType(field.Type) {
+			return nil, reflect.StructField{}, fmt.Errorf("field '%s' is not a struct", name)
+		}
+		typ = derefType(field.Type)
+	}
+	return nil, reflect.StructField{}, fmt.Errorf("empty path")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeRules decodes a JSON object, or the YAML equivalent, of types to field paths to rules
+func decodeRules(data []byte) (map[string]map[string]string, error) {
+	doc := make(map[string]map[string]string)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	var fields map[string]string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(stripComment(line), " \t\r")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			key, ok = strings.CutSuffix(strings.TrimSpace(line), ":")
+		}
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected 'key: value'", i+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if err := checkPlainYAML(key); err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		if err := checkPlainYAML(value); err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		if indented && value == "" {
+			return nil, fmt.Errorf("line %d: expected the rules of field '%s'", i+1, key)
+		}
+		key, err := unquoteYAML(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		value, err = unquoteYAML(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		if !indented {
+			if value != "" {
+				return nil, fmt.Errorf("line %d: expected the fields of type '%s'", i+1, key)
+			}
+			fields = make(map[string]string)
+			doc[key] = fields
+			continue
+		}
+		if fields == nil {
+			return nil, fmt.Errorf("line %d: field '%s' without type", i+1, key)
+		}
+		fields[key] = value
+	}
+	return doc, nil
+}
+
+// stripComment removes a YAML comment, a '#' at the start of the line or after a space outside quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// checkPlainYAML rejects the YAML constructs LoadRules doesn't support, which start with an indicator
+func checkPlainYAML(s string) error {
+	if s != "" && strings.ContainsRune("[]{}|>&*!%@`?-", rune(s[0])) {
+		return fmt.Errorf("unsupported YAML '%s', quote it if it's a scalar", s)
+	}
+	return nil
+}
+
+func unquoteYAML(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strconv.Unquote(s)
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		return "", fmt.Errorf("unterminated quote")
+	}
+	return s, nil
+}
@@ -0,0 +1,182 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/shaopson/validator"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var defaultFuncs = map[string]Func{
+	"required": requiredFunc,
+	"len":      lenFunc,
+	"eq":       compareFunc("const"),
+	"gt":       compareFunc("exclusiveMinimum"),
+	"gte":      compareFunc("minimum"),
+	"lt":       compareFunc("exclusiveMaximum"),
+	"lte":      compareFunc("maximum"),
+	"email":    formatFunc("email"),
+	"ip":       ipFunc,
+	"phone":    patternFunc,
+	"number":   patternFunc,
+	"lower":    patternFunc,
+	"upper":    patternFunc,
+	"alpha":    patternFunc,
+	"username": patternFunc,
+	"password": patternFunc,
+	"prefix":   patternFunc,
+	"suffix":   patternFunc,
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// requiredFunc rejects the zero values, the property itself is required by its struct
+func requiredFunc(s Schema, flag validator.Flag, typ reflect.Type) error {
+	typ = derefType(typ)
+	if typ == timeType {
+		add(s, "not", zeroSchema(typ))
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		tighten(s, "minLength", 1, true)
+	case reflect.Bool:
+		add(s, "const", true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		add(s, "not", zeroSchema(typ))
+	}
+	return nil
+}
+
+func lenFunc(s Schema, flag validator.Flag, typ reflect.Type) error {
+	params := strings.SplitN(flag.Param, "-", 2)
+	args := make([]int, len(params))
+	for i, p := range params {
+		arg, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("'%s': invalid param '%s'", flag, flag.Param)
+		}
+		args[i] = arg
+	}
+	if len(args) == 1 {
+		args = append(args, args[0])
+	}
+	typ = derefType(typ)
+	var min, max string
+	switch typ.Kind() {
+	case reflect.String:
+		min, max = "minLength", "maxLength"
+	case reflect.Slice:
+		// the length of the base64 string isn't the length of the bytes
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		min, max = "minItems", "maxItems"
+	case reflect.Array:
+		min, max = "minItems", "maxItems"
+	case reflect.Map:
+		min, max = "minProperties", "maxProperties"
+	default:
+		return nil
+	}
+	tighten(s, min, args[0], true)
+	tighten(s, max, args[1], false)
+	return nil
+}
+
+// tighten sets a bound of the length, keeping the stricter one if it's set
+func tighten(s Schema, keyword string, n int, lower bool) {
+	if old, ok := s[keyword].(int); ok && (lower && old > n || !lower && old < n) {
+		return
+	}
+	s[keyword] = n
+}
+
+// compareFunc returns the Func of a comparison validator, only numbers are compared by the schema
+func compareFunc(keyword string) Func {
+	return func(s Schema, flag validator.Flag, typ reflect.Type) error {
+		typ = derefType(typ)
+		switch typ.Kind() {
+		case reflect.String:
+			if keyword == "const" {
+				add(s, keyword, flag.Param)
+			}
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			value, err := parseValue(flag.Param, typ)
+			if err != nil {
+				return fmt.Errorf("'%s': %s", flag, err)
+			}
+			add(s, keyword, value)
+		}
+		return nil
+	}
+}
+
+// formatFunc returns the Func of a validator checking a format of JSON Schema
+func formatFunc(format string) Func {
+	return func(s Schema, flag validator.Flag, typ reflect.Type) error {
+		add(s, "format", format)
+		return nil
+	}
+}
+
+func ipFunc(s Schema, flag validator.Flag, typ reflect.Type) error {
+	switch flag.Param {
+	case "":
+		add(s, "anyOf", []interface{}{Schema{"format": "ipv4"}, Schema{"format": "ipv6"}})
+	case "v4":
+		add(s, "format", "ipv4")
+	case "v6":
+		add(s, "format", "ipv6")
+	default:
+		return fmt.Errorf("'%s': invalid param '%s'", flag, flag.Param)
+	}
+	return nil
+}
+
+// patternFunc adds the pattern of a string validator, see validator.Pattern
+func patternFunc(s Schema, flag validator.Flag, typ reflect.Type) error {
+	if derefType(typ).Kind() != reflect.String {
+		return nil
+	}
+	pattern, ok := validator.Pattern(flag.Name, flag.Param)
+	if !ok {
+		return fmt.Errorf("'%s': invalid param '%s'", flag, flag.Param)
+	}
+	add(s, "pattern", pattern)
+	return nil
+}
+
+// parseValue parses a param as a value of typ, for the const, minimum and maximum keywords
+func parseValue(param string, typ reflect.Type) (interface{}, error) {
+	typ = derefType(typ)
+	var value interface{}
+	var err error
+	switch typ.Kind() {
+	case reflect.Bool:
+		value, err = strconv.ParseBool(param)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(param, 0, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err = strconv.ParseUint(param, 0, 64)
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(param, 64)
+	default:
+		value = param
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid param '%s'", param)
+	}
+	return value, nil
+}
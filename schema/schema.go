@@ -0,0 +1,667 @@
+// Package schema exports the validate tags of struct types as JSON Schema (draft 2020-12) documents,
+// so clients can check the same rules before sending the requests.
+//
+// The properties are named as encoding/json names the fields. The rules a schema can't express,
+// e.g. the *_field validators or the comparisons of time.Time, are left out, or exported as x- keywords,
+// see Generator.SetExtensions. JSON Schema counts the
+// length of strings in characters while len counts bytes, they only agree on ASCII strings.
+package schema
+
+import (
+	"fmt"
+	"github.com/shaopson/validator"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Draft is the dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+const tagName = "validate"
+
+// Schema is a JSON Schema object, keyed by keywords
+type Schema map[string]interface{}
+
+// Func adds the keywords of a validator to s, the schema of the value of typ the validator is applied to.
+// typ may be a pointer
+type Func func(s Schema, flag validator.Flag, typ reflect.Type) error
+
+// FlagsFunc returns the flags applied to a field of the struct type structTyp, ok is false if there are none,
+// see validator.Engine.FieldFlags
+type FlagsFunc func(structTyp reflect.Type, field reflect.StructField) (flags []validator.Flag, ok bool, err error)
+
+// Generator generates the schemas of struct types. The validators without a Func are left out of the
+// schemas, see RegisterFunc and SetExtensions. A Generator is safe for concurrent use
+type Generator struct {
+	tagName    string
+	flagsFunc  FlagsFunc
+	funcs      map[string]Func
+	extensions bool
+	lock       sync.RWMutex
+}
+
+func New() *Generator {
+	generator := &Generator{
+		tagName: tagName,
+		funcs:   make(map[string]Func),
+	}
+	for k, v := range defaultFuncs {
+		generator.funcs[k] = v
+	}
+	return generator
+}
+
+var defaultGenerator = New()
+
+// Generate returns the schema of the struct type of x with the default Generator
+func Generate(x interface{}) (Schema, error) {
+	return defaultGenerator.Generate(x)
+}
+
+// SetTagName sets the key of the validator tags, see validator.Engine.SetTagName
+func (self *Generator) SetTagName(name string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.tagName = name
+}
+
+// SetFlagsFunc sets the function returning the flags of the fields instead of parsing their tags,
+// e.g. Engine.FieldFlags for the rules loaded on an engine
+func (self *Generator) SetFlagsFunc(fn FlagsFunc) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.flagsFunc = fn
+}
+
+// RegisterFunc registers the Func of a validator, e.g. a custom validator registered on the engine,
+// it replaces the Func of a builtin validator
+func (self *Generator) RegisterFunc(flag string, fn Func) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.funcs[flag] = fn
+}
+
+// SetExtensions makes the generator export the rules without a schema equivalent as x- keywords,
+// e.g. "x-eq_field": "password", "x-password": 2 or the param of a validator without a Func
+func (self *Generator) SetExtensions(extensions bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.extensions = extensions
+}
+
+// Generate returns the schema document of the struct type of x, x is a struct, a pointer to a struct or
+// a reflect.Type. The named struct types nested in the type are defined in $defs
+func (self *Generator) Generate(x interface{}) (Schema, error) {
+	typ, err := structType(x)
+	if err != nil {
+		return nil, err
+	}
+	b := self.builder("#/$defs/")
+	b.refs[typ] = "#"
+	root, err := b.structSchema(typ)
+	if err != nil {
+		return nil, err
+	}
+	doc := Schema{"$schema": Draft}
+	if typ.Name() != "" {
+		doc["title"] = typ.Name()
+	}
+	for k, v := range root {
+		doc[k] = v
+	}
+	if len(b.defs) > 0 {
+		doc["$defs"] = b.defs
+	}
+	return doc, nil
+}
+
+// Definitions returns the schemas of the named struct types of types and of the struct types nested in them,
+// by name. The references point to prefix followed by the name, e.g. "#/components/schemas/" for the
+// components of an OpenAPI document
+func (self *Generator) Definitions(prefix string, types ...interface{}) (Schema, error) {
+	b := self.builder(prefix)
+	for _, x := range types {
+		typ, err := structType(x)
+		if err != nil {
+			return nil, err
+		}
+		if typ.Name() == "" {
+			return nil, fmt.Errorf("schema: Definitions only support named types")
+		}
+		if _, err := b.typeSchema(typ); err != nil {
+			return nil, err
+		}
+	}
+	return b.defs, nil
+}
+
+func (self *Generator) builder(prefix string) *builder {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	b := &builder{
+		tagName:    self.tagName,
+		flagsFunc:  self.flagsFunc,
+		funcs:      make(map[string]Func, len(self.funcs)),
+		extensions: self.extensions,
+		prefix:     prefix,
+		refs:       make(map[reflect.Type]string),
+		names:      make(map[string]bool),
+		defs:       Schema{},
+	}
+	for k, v := range self.funcs {
+		b.funcs[k] = v
+	}
+	return b
+}
+
+func structType(x interface{}) (reflect.Type, error) {
+	typ, ok := x.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(x)
+	}
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: only support 'Struct' type, got '%v'", typ)
+	}
+	return typ, nil
+}
+
+// builder builds a schema document
+type builder struct {
+	tagName    string
+	flagsFunc  FlagsFunc
+	funcs      map[string]Func
+	extensions bool
+	// refs are the references of the struct types defined in defs, prefix followed by their names
+	prefix string
+	refs   map[reflect.Type]string
+	names  map[string]bool
+	defs   Schema
+}
+
+// property is a field of a struct, with the name encoding/json gives it
+type property struct {
+	field reflect.StructField
+	name  string
+	// owner is the struct type declaring the field
+	owner reflect.Type
+}
+
+// object is the schema of a struct being built
+type object struct {
+	properties Schema
+	required   []string
+	allOf      []interface{}
+	// byField are the properties by the Go names of the fields, for the conditional validators
+	byField map[string]*property
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (self *builder) typeSchema(typ reflect.Type) (Schema, error) {
+	if typ == timeType {
+		return Schema{"type": "string", "format": "date-time"}, nil
+	}
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return self.typeSchema(typ.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Schema{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}, nil
+	case reflect.String:
+		return Schema{"type": "string"}, nil
+	case reflect.Slice:
+		// encoding/json encodes []byte as a base64 string
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := self.typeSchema(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items}, nil
+	case reflect.Array:
+		items, err := self.typeSchema(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items, "minItems": typ.Len(), "maxItems": typ.Len()}, nil
+	case reflect.Map:
+		switch typ.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return nil, fmt.Errorf("schema: not support map key type '%s'", typ.Key())
+		}
+		elem, err := self.typeSchema(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "object", "additionalProperties": elem}, nil
+	case reflect.Interface:
+		return Schema{}, nil
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return self.structSchema(typ)
+		}
+		if ref, ok := self.refs[typ]; ok {
+			return Schema{"$ref": ref}, nil
+		}
+		name := self.defName(typ)
+		self.refs[typ] = self.prefix + name
+		s, err := self.structSchema(typ)
+		if err != nil {
+			return nil, err
+		}
+		self.defs[name] = s
+		return Schema{"$ref": self.refs[typ]}, nil
+	}
+	return nil, fmt.Errorf("schema: not support type '%s'", typ)
+}
+
+// defName returns the name of a struct type in $defs, qualified by its package if the name is taken
+func (self *builder) defName(typ reflect.Type) string {
+	name := typ.Name()
+	if self.names[name] {
+		name = strings.ReplaceAll(typ.PkgPath(), "/", ".") + "." + name
+	}
+	self.names[name] = true
+	return name
+}
+
+func (self *builder) structSchema(typ reflect.Type) (Schema, error) {
+	obj := &object{
+		properties: Schema{},
+		byField:    make(map[string]*property),
+	}
+	var props []*property
+	collectProperties(typ, &props)
+	for _, prop := range props {
+		obj.byField[prop.field.Name] = prop
+	}
+	for _, prop := range props {
+		s, err := self.typeSchema(prop.field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: Field '%s'", err, prop.field.Name)
+		}
+		flags, ok, err := self.fieldFlags(prop)
+		if err != nil {
+			return nil, fmt.Errorf("schema: Field '%s': %w", prop.field.Name, err)
+		}
+		if !ok {
+			obj.properties[prop.name] = nullable(s, prop.field.Type)
+			continue
+		}
+		if s, err = self.apply(s, flags, prop.field.Type, obj, prop.name); err != nil {
+			return nil, fmt.Errorf("schema: Field '%s': %w", prop.field.Name, err)
+		}
+		obj.properties[prop.name] = s
+	}
+	s := Schema{"type": "object", "properties": obj.properties}
+	if len(obj.required) > 0 {
+		s["required"] = obj.required
+	}
+	if len(obj.allOf) > 0 {
+		s["allOf"] = obj.allOf
+	}
+	return s, nil
+}
+
+// fieldFlags returns the flags of the field of prop, ok is false if it has none
+func (self *builder) fieldFlags(prop *property) (flags []validator.Flag, ok bool, err error) {
+	if self.flagsFunc != nil {
+		return self.flagsFunc(prop.owner, prop.field)
+	}
+	tag, ok := prop.field.Tag.Lookup(self.tagName)
+	if !ok || tag == "-" {
+		return nil, false, nil
+	}
+	flags, err = validator.ParseTag(tag)
+	return flags, err == nil, err
+}
+
+// collectProperties collects the fields encoding/json encodes, the fields of embedded structs are promoted
+func collectProperties(typ reflect.Type, props *[]*property) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			t := field.Type
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct {
+				collectProperties(t, props)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		*props = append(*props, &property{field: field, name: name, owner: typ})
+	}
+}
+
+// apply adds the keywords of the flags to s, the schema of a value of typ. obj is the struct the value
+// is a field of, named name, nil for the elements of the fields
+func (self *builder) apply(s Schema, flags []validator.Flag, typ reflect.Type, obj *object, name string) (Schema, error) {
+	var fieldFlags, elemFlags []validator.Flag
+	dive := false
+	for i, f := range flags {
+		if f.Name == "dive" {
+			fieldFlags, elemFlags, dive = flags[:i], flags[i+1:], true
+			break
+		}
+	}
+	if !dive {
+		fieldFlags = flags
+	}
+	rules := s
+	blank := false
+	for _, f := range fieldFlags {
+		if f.Name == "blank" {
+			blank = true
+			rules = Schema{}
+		}
+	}
+	required := false
+	for _, f := range fieldFlags {
+		if f.Name == "required" {
+			required = true
+		}
+		if err := self.applyFlag(rules, f, typ, obj, name); err != nil {
+			return nil, err
+		}
+	}
+	if dive {
+		if err := self.applyDive(s, elemFlags, typ); err != nil {
+			return nil, err
+		}
+	}
+	if blank && len(rules) > 0 {
+		// the rules don't apply to the zero value
+		add(s, "anyOf", []interface{}{zeroSchema(typ), rules})
+	}
+	if required {
+		return s, nil
+	}
+	return nullable(s, typ), nil
+}
+
+func (self *builder) applyFlag(s Schema, f validator.Flag, typ reflect.Type, obj *object, name string) error {
+	if len(f.Alternatives) > 0 {
+		alternatives := make([]interface{}, len(f.Alternatives))
+		for i, alternative := range f.Alternatives {
+			sub := Schema{}
+			if err := self.applyFlag(sub, alternative, typ, nil, name); err != nil {
+				return err
+			}
+			alternatives[i] = sub
+		}
+		add(s, "anyOf", alternatives)
+		return nil
+	}
+	if f.Negate {
+		sub := Schema{}
+		f.Negate = false
+		if err := self.applyFlag(sub, f, typ, nil, name); err != nil {
+			return err
+		}
+		if len(sub) > 0 {
+			add(s, "not", sub)
+		}
+		return nil
+	}
+	if validator.ControlFlag(f.Name) {
+		return nil
+	}
+	if f.Name == "required" && obj != nil {
+		obj.required = append(obj.required, name)
+	}
+	if cond, ok := conditions[f.Name]; ok {
+		if obj == nil {
+			return nil
+		}
+		return self.applyCondition(obj, cond, f, name)
+	}
+	fn, ok := self.funcs[f.Name]
+	if ok {
+		if err := fn(s, f, typ); err != nil {
+			return err
+		}
+	}
+	if self.extensions {
+		if value, ok := self.extension(f, typ, obj, ok); ok {
+			s["x-"+f.Name] = value
+		}
+	}
+	return nil
+}
+
+// extension returns the value of the x- keyword of a rule without a schema equivalent, ok is false
+// if the rule has one. hasFunc reports whether the validator has a Func
+func (self *builder) extension(f validator.Flag, typ reflect.Type, obj *object, hasFunc bool) (interface{}, bool) {
+	switch f.Name {
+	case "eq_field", "gt_field", "gte_field", "lt_field", "lte_field":
+		if obj != nil {
+			if prop, ok := obj.byField[f.Param]; ok {
+				return prop.name, true
+			}
+		}
+		return f.Param, true
+	case "password":
+		if level, err := strconv.Atoi(f.Param); err == nil {
+			return level, true
+		}
+		return 3, true
+	case "phone":
+		return true, true
+	case "eq", "gt", "gte", "lt", "lte":
+		// only numbers are compared by the schema, and strings by const
+		switch derefType(typ).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return nil, false
+		case reflect.String:
+			if f.Name == "eq" {
+				return nil, false
+			}
+		}
+		return f.Param, true
+	}
+	if hasFunc {
+		return nil, false
+	}
+	if f.Param == "" {
+		return true, true
+	}
+	return f.Param, true
+}
+
+// applyDive applies the flags to the items, and the property names, of the schema of a slice, an array or a map
+func (self *builder) applyDive(s Schema, flags []validator.Flag, typ reflect.Type) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if len(flags) > 0 && flags[0].Name == "keys" {
+		end := -1
+		for i, f := range flags {
+			if f.Name == "endkeys" {
+				end = i
+				break
+			}
+		}
+		if end < 0 || typ.Kind() != reflect.Map {
+			return fmt.Errorf("invalid 'keys'")
+		}
+		if typ.Key().Kind() == reflect.String {
+			names, err := self.apply(Schema{"type": "string"}, flags[1:end], typ.Key(), nil, "")
+			if err != nil {
+				return err
+			}
+			delete(names, "type")
+			if len(names) > 0 {
+				s["propertyNames"] = names
+			}
+		}
+		flags = flags[end+1:]
+	}
+	var key string
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		key = "items"
+	case reflect.Map:
+		key = "additionalProperties"
+	default:
+		return fmt.Errorf("cannot dive into type '%s'", typ)
+	}
+	elem, ok := s[key].(Schema)
+	if !ok {
+		return nil
+	}
+	elem, err := self.apply(elem, flags, typ.Elem(), nil, "")
+	if err != nil {
+		return err
+	}
+	s[key] = elem
+	return nil
+}
+
+// condition describes a conditional validator
+type condition struct {
+	pairs bool
+	// match is set if the rule applies when the fields match, rather than when they don't
+	match bool
+	// present is set if the fields are checked for presence rather than absence, for the *_with validators
+	present  bool
+	excluded bool
+}
+
+var conditions = map[string]condition{
+	"required_if":      {pairs: true, match: true},
+	"required_unless":  {pairs: true},
+	"required_with":    {match: true, present: true},
+	"required_without": {match: true},
+	"excluded_if":      {pairs: true, match: true, excluded: true},
+	"excluded_unless":  {pairs: true, excluded: true},
+	"excluded_with":    {match: true, present: true, excluded: true},
+	"excluded_without": {match: true, excluded: true},
+}
+
+// applyCondition adds the if/then schema of a conditional validator to the struct. A field is present
+// in the schema if the property is, which is close to the non-zero values of the engine
+func (self *builder) applyCondition(obj *object, cond condition, f validator.Flag, name string) error {
+	items, err := validator.SplitParam(f.Param)
+	if err != nil {
+		return fmt.Errorf("'%s': %s", f, err)
+	}
+	var ifSchema Schema
+	if cond.pairs {
+		if len(items) == 0 || len(items)%2 != 0 {
+			return fmt.Errorf("'%s': invalid param '%s'", f, f.Param)
+		}
+		props := Schema{}
+		var names []string
+		for i := 0; i < len(items); i += 2 {
+			prop, ok := obj.byField[items[i]]
+			if !ok {
+				return fmt.Errorf("'%s': field '%s' not found", f, items[i])
+			}
+			value, err := parseValue(items[i+1], prop.field.Type)
+			if err != nil {
+				return fmt.Errorf("'%s': %s", f, err)
+			}
+			props[prop.name] = Schema{"const": value}
+			names = append(names, prop.name)
+		}
+		ifSchema = Schema{"properties": props, "required": names}
+	} else {
+		if len(items) == 0 {
+			return fmt.Errorf("'%s': missing param", f)
+		}
+		var anyOf []interface{}
+		for _, item := range items {
+			prop, ok := obj.byField[item]
+			if !ok {
+				return fmt.Errorf("'%s': field '%s' not found", f, item)
+			}
+			var s Schema = Schema{"required": []string{prop.name}}
+			if !cond.present {
+				s = Schema{"not": s}
+			}
+			anyOf = append(anyOf, s)
+		}
+		ifSchema = Schema{"anyOf": anyOf}
+		if len(anyOf) == 1 {
+			ifSchema = anyOf[0].(Schema)
+		}
+	}
+	var rule Schema = Schema{"required": []string{name}}
+	if cond.excluded {
+		rule = Schema{"not": rule}
+	}
+	if cond.match {
+		obj.allOf = append(obj.allOf, Schema{"if": ifSchema, "then": rule})
+	} else {
+		obj.allOf = append(obj.allOf, Schema{"if": ifSchema, "else": rule})
+	}
+	return nil
+}
+
+// add adds a keyword to s, a keyword already set is added in allOf so both apply
+func add(s Schema, keyword string, value interface{}) {
+	if _, ok := s[keyword]; !ok {
+		s[keyword] = value
+		return
+	}
+	allOf, _ := s["allOf"].([]interface{})
+	s["allOf"] = append(allOf, Schema{keyword: value})
+}
+
+// nullable allows null in the schema of a pointer, which encoding/json encodes nil as
+func nullable(s Schema, typ reflect.Type) Schema {
+	if typ.Kind() != reflect.Pointer {
+		return s
+	}
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []string{t, "null"}
+		return s
+	}
+	return Schema{"anyOf": []interface{}{s, Schema{"type": "null"}}}
+}
+
+// zeroSchema returns the schema of the zero value of typ, which the blank flag omits
+func zeroSchema(typ reflect.Type) Schema {
+	if typ == timeType {
+		return Schema{"const": time.Time{}.Format(time.RFC3339)}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return Schema{"const": false}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return Schema{"const": 0}
+	case reflect.String:
+		return Schema{"const": ""}
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return Schema{"type": "null"}
+	}
+	return Schema{}
+}
@@ -0,0 +1,163 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// structFlag is the flag of the feedbacks reported by struct level validation
+const structFlag = "struct"
+
+// Validatable is implemented by structs that validate themselves. Engine.Validate calls
+// ValidateStruct after the fields of the struct are validated, a *ValidationError or *FieldError
+// returned is merged into the result, any other error is reported against the struct itself.
+// The method is named apart from the common Validate methods, which often call Engine.Validate
+// on their receiver; ValidateStruct must not.
+type Validatable interface {
+	ValidateStruct() error
+}
+
+// StructLevelValidatable is implemented by structs that report errors against their fields
+type StructLevelValidatable interface {
+	ValidateWith(*StructLevel)
+}
+
+// StructValidator validates a struct type as a whole, see Engine.RegisterStructValidator
+type StructValidator func(*StructLevel)
+
+var validatableType = reflect.TypeOf((*Validatable)(nil)).Elem()
+var structLevelValidatableType = reflect.TypeOf((*StructLevelValidatable)(nil)).Elem()
+
+// StructLevel gives struct level validators access to the struct being validated
+// and reports errors against its fields
+type StructLevel struct {
+	Engine      *Engine
+	Struct      reflect.Value
+	prefix      string
+	structError *ValidationError
+}
+
+// Context returns the context given to ValidateCtx, context.Background() otherwise
+func (self *StructLevel) Context() context.Context {
+	if self.structError.ctx == nil {
+		return context.Background()
+	}
+	return self.structError.ctx
+}
+
+// ReportError reports an error against the named field of the struct, the error is merged
+// with the feedbacks of the field. An empty field reports the error against the struct itself.
+// The feedback handler registered for flag is applied to the message.
+func (self *StructLevel) ReportError(field string, flag string, param string, message string) {
+	v := &Validation{
+		StructField: reflect.StructField{Name: self.Struct.Type().Name(), Type: self.Struct.Type()},
+		Field:       self.Struct,
+		Struct:      self.Struct,
+		Flag:        flag,
+		Param:       param,
+		ctx:         self.structError.ctx,
+	}
+	self.Engine.lock.RLock()
+	handler := self.Engine.FeedbackHandlers[flag]
+	v.nameFunc = self.Engine.tagNameFunc
+	self.Engine.lock.RUnlock()
+	name := ""
+	path := self.prefix
+	if field != "" {
+		name = v.FieldName(field)
+		path = joinPath(self.prefix, name)
+		if fieldTyp, ok := self.Struct.Type().FieldByName(field); ok {
+			v.StructField = fieldTyp
+			v.Field = self.Struct.FieldByIndex(fieldTyp.Index)
+		} else {
+			v.StructField = reflect.StructField{Name: field}
+		}
+	}
+	feedback := v.Error(message).(*Feedback)
+	if handler != nil {
+		feedback.s = handler(feedback)
+	}
+	self.structError.addFeedback(v.StructField, name, path, feedback)
+}
+
+// RegisterStructValidator registers a validator for the struct type of typ, use it
+// for types you can't add a Validate method to. typ is a value or a pointer of the type
+func (self *Engine) RegisterStructValidator(typ interface{}, fn StructValidator) {
+	t := reflect.TypeOf(typ)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.structValidators[t] = fn
+	self.resetPlans()
+}
+
+// validateStructLevel runs the struct level validators of the plan on structVal.
+// The Validate and ValidateWith methods of an embedded struct are promoted to the
+// outer struct, so they are only called on the outer struct
+func (self *Engine) validateStructLevel(plan *structPlan, structVal reflect.Value, prefix string, embedded bool, structError *ValidationError) {
+	validate := plan.validate && !embedded
+	validateWith := plan.validateWith && !embedded
+	if !validateWith && !validate && plan.structValidator == nil {
+		return
+	}
+	if structError.stop {
+		structError.Truncated = true
+		return
+	}
+	if !structVal.CanInterface() {
+		return
+	}
+	// methods with a pointer receiver need an addressable struct
+	if !structVal.CanAddr() {
+		copied := reflect.New(structVal.Type()).Elem()
+		copied.Set(structVal)
+		structVal = copied
+	}
+	sl := &StructLevel{
+		Engine:      self,
+		Struct:      structVal,
+		prefix:      prefix,
+		structError: structError,
+	}
+	if validateWith {
+		structVal.Addr().Interface().(StructLevelValidatable).ValidateWith(sl)
+	}
+	if validate {
+		if err := structVal.Addr().Interface().(Validatable).ValidateStruct(); err != nil {
+			sl.merge(err)
+		}
+	}
+	if plan.structValidator != nil {
+		plan.structValidator(sl)
+	}
+}
+
+// merge adds the error returned by Validatable.Validate to the result
+func (self *StructLevel) merge(err error) {
+	switch e := err.(type) {
+	case *ValidationError:
+		for _, fieldError := range e.Detail {
+			for _, feedback := range fieldError.Feedbacks {
+				self.structError.addFeedback(fieldError.Field, fieldError.Name, self.path(fieldError.Path), feedback)
+			}
+		}
+	case *FieldError:
+		for _, feedback := range e.Feedbacks {
+			self.structError.addFeedback(e.Field, e.Name, self.path(e.Path), feedback)
+		}
+	case *Feedback:
+		self.structError.addFeedback(e.Validation.StructField, "", self.prefix, e)
+	default:
+		self.ReportError("", structFlag, "", err.Error())
+	}
+}
+
+// path returns the path of an error of the struct from the top struct, an empty path is the struct itself
+func (self *StructLevel) path(path string) string {
+	if path == "" {
+		return self.prefix
+	}
+	return joinPath(self.prefix, path)
+}
@@ -0,0 +1,225 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orFlag and notFlag are the flags of the feedbacks of OR groups and negated validators
+const orFlag = "or"
+const notFlag = "not"
+
+// Flag is a validator name with its param, as written in the tag.
+// An OR group is a flag named "or" holding the alternatives
+type Flag struct {
+	Name         string
+	Param        string
+	Negate       bool
+	Alternatives []Flag
+}
+
+// String formats the flag as it would be written in the tag, without quoting
+func (self Flag) String() string {
+	if len(self.Alternatives) > 0 {
+		items := make([]string, len(self.Alternatives))
+		for i, alternative := range self.Alternatives {
+			items[i] = alternative.String()
+		}
+		return strings.Join(items, "|")
+	}
+	s := self.Name
+	if self.Param != "" {
+		s += ":" + self.Param
+	}
+	if self.Negate {
+		s = "!" + s
+	}
+	return s
+}
+
+// flags that control the validation and can't be grouped or negated
+var controlFlags = map[string]bool{
+	omitemptyFlag: true,
+	skipFlag:      true,
+	diveFlag:      true,
+	keysFlag:      true,
+	endkeysFlag:   true,
+}
+
+// ParseTag parses a tag into flags, for the tools that read the tags without reflection, e.g. cmd/validatorvet
+func ParseTag(tag string) ([]Flag, error) {
+	return parseFlags(tag)
+}
+
+// ControlFlag reports whether name is a flag that controls the validation rather than a validator,
+// e.g. dive or blank
+func ControlFlag(name string) bool {
+	return controlFlags[name]
+}
+
+// tagParser splits a tag into flags. Flags are separated by ',' and the alternatives
+// of an OR group by '|'. A flag is negated by a leading '!' or 'not:'. The param follows
+// the first ':' and may be quoted with single quotes, '\' escapes the next character.
+type tagParser struct {
+	tag    string
+	result []Flag
+	group  []Flag
+	// the current flag, literal marks the bytes of buf that were quoted or escaped
+	buf     []byte
+	literal []bool
+	colon   int
+	negate  bool
+}
+
+// parseFlags parses the tag into flags, keeping the order they are declared in
+func parseFlags(tag string) ([]Flag, error) {
+	p := &tagParser{
+		tag:    tag,
+		result: make([]Flag, 0),
+		colon:  -1,
+	}
+	return p.parse()
+}
+
+// error returns an error wrapping ErrInvalidTag
+func (self *tagParser) error(format string, a ...any) error {
+	return &ConfigError{Err: ErrInvalidTag, s: fmt.Sprintf("invalid tag '%s': ", self.tag) + fmt.Sprintf(format, a...)}
+}
+
+func (self *tagParser) parse() ([]Flag, error) {
+	quoted := false
+	for i := 0; i < len(self.tag); i++ {
+		c := self.tag[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(self.tag) {
+				return nil, self.error("trailing '\\'")
+			}
+			i++
+			self.write(self.tag[i], true)
+		case quoted:
+			if c == '\'' {
+				quoted = false
+			} else {
+				self.write(c, true)
+			}
+		case c == '\'':
+			quoted = true
+		case c == '!' && self.colon < 0 && self.blank():
+			self.negate = true
+		case c == ':' && self.colon < 0:
+			if !self.negate && strings.TrimSpace(string(self.buf)) == notFlag {
+				self.negate = true
+				self.buf = self.buf[:0]
+				self.literal = self.literal[:0]
+				continue
+			}
+			self.colon = len(self.buf)
+			self.write(c, false)
+		case c == '|':
+			if err := self.endFlag(true); err != nil {
+				return nil, err
+			}
+		case c == ',':
+			if err := self.endFlag(false); err != nil {
+				return nil, err
+			}
+			if err := self.endGroup(); err != nil {
+				return nil, err
+			}
+		default:
+			self.write(c, false)
+		}
+	}
+	if quoted {
+		return nil, self.error("unterminated quote")
+	}
+	if err := self.endFlag(false); err != nil {
+		return nil, err
+	}
+	if err := self.endGroup(); err != nil {
+		return nil, err
+	}
+	return self.result, nil
+}
+
+func (self *tagParser) write(c byte, literal bool) {
+	self.buf = append(self.buf, c)
+	self.literal = append(self.literal, literal)
+}
+
+// blank reports whether the current flag has only unquoted spaces so far
+func (self *tagParser) blank() bool {
+	for i, c := range self.buf {
+		if c != ' ' || self.literal[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// trim trims the unquoted spaces of buf[start:end]
+func (self *tagParser) trim(start, end int) string {
+	for start < end && self.buf[start] == ' ' && !self.literal[start] {
+		start++
+	}
+	for end > start && self.buf[end-1] == ' ' && !self.literal[end-1] {
+		end--
+	}
+	return string(self.buf[start:end])
+}
+
+// endFlag ends the current flag, alternative is set if it is followed by '|'
+func (self *tagParser) endFlag(alternative bool) error {
+	var f Flag
+	if self.colon < 0 {
+		f.Name = self.trim(0, len(self.buf))
+	} else {
+		f.Name = self.trim(0, self.colon)
+		f.Param = self.trim(self.colon+1, len(self.buf))
+	}
+	f.Negate = self.negate
+	self.buf = self.buf[:0]
+	self.literal = self.literal[:0]
+	self.colon = -1
+	self.negate = false
+	if f.Name == "" {
+		// empty flags between commas are ignored, as in "required,,email"
+		if !f.Negate && f.Param == "" && !alternative && len(self.group) == 0 {
+			return nil
+		}
+		return self.error("missing validator name")
+	}
+	self.group = append(self.group, f)
+	return nil
+}
+
+// endGroup adds the flags since the last ',' to the result, as an OR group if there are several
+func (self *tagParser) endGroup() error {
+	defer func() {
+		self.group = nil
+	}()
+	switch len(self.group) {
+	case 0:
+		return nil
+	case 1:
+		f := self.group[0]
+		if f.Negate && controlFlags[f.Name] {
+			return self.error("'%s' can't be negated", f.Name)
+		}
+		self.result = append(self.result, f)
+		return nil
+	}
+	for _, f := range self.group {
+		if controlFlags[f.Name] {
+			return self.error("'%s' can't be used in an OR group", f.Name)
+		}
+	}
+	group := Flag{
+		Name:         orFlag,
+		Alternatives: self.group,
+	}
+	group.Param = group.String()
+	self.result = append(self.result, group)
+	return nil
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"testing"
+	"time"
+)
+
+type BuilderAddress struct {
+	City string
+	Zip  string
+}
+
+type BuilderUser struct {
+	Name     string `validate:"required"`
+	Email    string
+	Age      int
+	Password string
+	Confirm  string
+	Type     string
+	Company  string
+	Tags     []string
+	Address  BuilderAddress
+}
+
+type BuilderBooking struct {
+	Start time.Time
+	Kind  string
+	Note  string
+	Room  string
+}
+
+// TaggedUser declares the rules of TestRules in tags
+type TaggedUser struct {
+	Name     string `validate:"len:2-5"`
+	Email    string `validate:"required,email"`
+	Age      int    `validate:"gte:18"`
+	Password string
+	Confirm  string `validate:"eq_field:Password"`
+	Type     string
+	Company  string   `validate:"required_if:Type business"`
+	Tags     []string `validate:"dive,lower|number"`
+	Address  struct {
+		City string
+		Zip  string `validate:"blank,!prefix:00"`
+	}
+}
+
+func TestRules(t *testing.T) {
+	v := validator.New()
+	err := v.RegisterRules(validator.Rules[BuilderUser]().
+		Field("Name", validator.LenRange(2, 5)).
+		Field("Email", validator.Required(), validator.Email()).
+		Field("Age", validator.Gte(18)).
+		Field("Confirm", validator.EqField("Password")).
+		Field("Company", validator.RequiredIf("Type", "business")).
+		Field("Tags", validator.Dive(), validator.Or(validator.Lower(), validator.Number())).
+		Field("Address.Zip", validator.Blank(), validator.Not(validator.Prefix("00"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagged := TaggedUser{Name: "Jonathan", Email: "tom", Age: 16, Password: "a", Confirm: "b", Type: "business", Tags: []string{"go", "Go", "42"}}
+	tagged.Address.Zip = "00123"
+	user := BuilderUser{Name: "Jonathan", Email: "tom", Age: 16, Password: "a", Confirm: "b", Type: "business", Tags: []string{"go", "Go", "42"}}
+	user.Address.Zip = "00123"
+	err = v.Validate(&user)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	expected := validator.New().Validate(&tagged).(*validator.ValidationError).Map()
+	m := e.Map()
+	for path, message := range expected {
+		if m[path] != message {
+			t.Errorf("%s: expected '%s', got '%s'", path, message, m[path])
+		}
+	}
+	if len(m) != len(expected) || len(m) != 7 {
+		t.Errorf("unexpected errors: %v", m)
+	}
+}
+
+func TestRulesError(t *testing.T) {
+	cases := []struct {
+		rules  validator.RuleSource
+		target error
+	}{
+		{validator.Rules[BuilderUser]().Field("Phone", validator.Required()), validator.ErrInvalidRules},
+		{validator.Rules[BuilderUser]().Field("Name", validator.Not(validator.Dive())), validator.ErrInvalidTag},
+		{validator.Rules[BuilderUser]().Field("Name", validator.Rule("uuid", "")), validator.ErrUnregisteredValidator},
+		{validator.Rules[BuilderUser]().Field("Confirm", validator.EqField("Secret")), validator.ErrInvalidParam},
+		{validator.Rules[int](), validator.ErrInvalidValue},
+	}
+	for i, c := range cases {
+		if err := validator.New().RegisterRules(c.rules); !errors.Is(err, c.target) {
+			t.Errorf("case %d: expected %v, got %v", i, c.target, err)
+		}
+	}
+}
+
+func TestRulesTimeZone(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	v := validator.New()
+	// 2024-01-01 00:00:00 UTC
+	err := v.RegisterRules(validator.Rules[BuilderBooking]().
+		Field("Start", validator.Gt(time.Date(2024, 1, 1, 8, 0, 0, 0, shanghai))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(&BuilderBooking{Start: time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC)}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.Validate(&BuilderBooking{Start: time.Date(2024, 1, 1, 7, 0, 0, 0, shanghai)}); err == nil {
+		t.Error("expected error for a time before the param")
+	}
+}
+
+func TestRulesCondition(t *testing.T) {
+	start := time.Date(2020, 1, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	v := validator.New()
+	err := v.RegisterRules(validator.Rules[BuilderBooking]().
+		Field("Note", validator.RequiredIf("Kind", "a b")).
+		Field("Room", validator.RequiredIf("Start", start), validator.ExcludedIf("Kind", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		booking  BuilderBooking
+		expected map[string]string
+	}{
+		{BuilderBooking{Kind: "a b"}, map[string]string{"Note": "field is required when Kind is a b"}},
+		{BuilderBooking{Kind: "a", Start: start.UTC()}, map[string]string{"Room": "field is required when Start is 2020-01-01 00:00:00"}},
+		{BuilderBooking{Room: "101"}, map[string]string{"Room": "field must be empty when Kind is "}},
+		{BuilderBooking{Kind: "a", Room: "101", Start: start}, nil},
+	}
+	for i, c := range cases {
+		err := v.Validate(&c.booking)
+		if c.expected == nil {
+			if err != nil {
+				t.Errorf("case %d: %v", i, err)
+			}
+			continue
+		}
+		e, ok := err.(*validator.ValidationError)
+		if !ok {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		m := e.Map()
+		for path, message := range c.expected {
+			if m[path] != message {
+				t.Errorf("case %d: %s: expected '%s', got '%s'", i, path, message, m[path])
+			}
+		}
+		if len(m) != len(c.expected) {
+			t.Errorf("case %d: unexpected errors: %v", i, m)
+		}
+	}
+}
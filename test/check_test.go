@@ -0,0 +1,132 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type checkAddress struct {
+	Zip  string `validate:"len:abc"`
+	City string `validate:"required"`
+}
+
+type checkForm struct {
+	Name     string         `validate:"requred"`
+	Age      int            `validate:"gt:abc"`
+	Count    int            `validate:"lower"`
+	Nick     string         `validate:"'required"`
+	Confirm  string         `validate:"eq_field:Passwd"`
+	Start    time.Time      `validate:"lt_field:Count"`
+	Tax      string         `validate:"required_if:Kind business"`
+	IP       string         `validate:"ip:v5|email"`
+	Tags     []string       `validate:"dive,keys,required,endkeys"`
+	Limits   map[string]int `validate:"dive,keys,len:2-4,endkeys,gte:x"`
+	Value    any            `validate:"gt:1"`
+	Address  checkAddress
+	Password string `validate:"password:4"`
+}
+
+func TestCheck(t *testing.T) {
+	v := validator.New()
+	err := v.Check(&checkForm{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	expected := []struct {
+		field  string
+		target error
+	}{
+		{"checkForm.Name", validator.ErrUnregisteredValidator},
+		{"checkForm.Age", validator.ErrInvalidParam},
+		{"checkForm.Count", validator.ErrUnsupportedType},
+		{"checkForm.Nick", validator.ErrInvalidTag},
+		{"checkForm.Confirm", validator.ErrInvalidParam},
+		{"checkForm.Start", validator.ErrUnsupportedType},
+		{"checkForm.Tax", validator.ErrInvalidParam},
+		{"checkForm.IP", validator.ErrInvalidParam},
+		{"checkForm.Tags", validator.ErrUnsupportedType},
+		{"checkForm.Limits[*]", validator.ErrInvalidParam},
+		{"checkForm.Address.Zip", validator.ErrInvalidParam},
+		{"checkForm.Password", validator.ErrInvalidParam},
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != len(expected) {
+		t.Errorf("unexpected errors:\n%v", err)
+	}
+	for i, e := range expected {
+		if i >= len(errs) {
+			break
+		}
+		var configErr *validator.ConfigError
+		if !errors.As(errs[i], &configErr) || configErr.Field != e.field || !errors.Is(errs[i], e.target) {
+			t.Errorf("unexpected error %d: %v", i, errs[i])
+		}
+	}
+	if !strings.Contains(err.Error(), "Field 'checkForm.Name': Unregistered validator 'requred'") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestCheckValid(t *testing.T) {
+	v := validator.New()
+	v.RegisterValidator("lower", func(v *validator.Validation) error {
+		return nil
+	})
+	if err := v.Check(&UserForm{}, &companyForm{}, &diveForm{}, &nestedOrder{}, &tagForm{}, &FeedbackForm{}, &contactForm{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	type custom struct {
+		Count int `validate:"lower"`
+	}
+	if err := v.Check(custom{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.Check(1); !errors.Is(err, validator.ErrInvalidValue) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMustRegisterType(t *testing.T) {
+	v := validator.New()
+	v.MustRegisterType(&companyForm{})
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	v.MustRegisterType(&checkForm{})
+}
+
+func TestCheckFlag(t *testing.T) {
+	cases := []struct {
+		tag    string
+		value  interface{}
+		target error
+	}{
+		{"len:6-18", "", nil},
+		{"len:abc", "", validator.ErrInvalidParam},
+		{"email", 1, validator.ErrUnsupportedType},
+		{"emial", "", validator.ErrUnregisteredValidator},
+		{"ip:v4|gt:x", 1.5, validator.ErrUnsupportedType},
+		{"eq_field:Missing", "", nil},
+		{"required_if:Kind", "", nil},
+		{"gt:1", nil, nil},
+	}
+	for _, c := range cases {
+		flags, err := validator.ParseTag(c.tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = validator.CheckFlag(flags[0], reflect.TypeOf(c.value))
+		if (c.target == nil && err != nil) || !errors.Is(err, c.target) {
+			t.Errorf("%s: expected %v, got %v", c.tag, c.target, err)
+		}
+	}
+	if !validator.ControlFlag("dive") || validator.ControlFlag("required") {
+		t.Error("unexpected control flags")
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type codeForm struct {
+	Password string `validate:"len:6-18,password:2"`
+	Confirm  string `validate:"eq_field:Password"`
+	Age      int    `validate:"gte:18"`
+	Pin      string `validate:"len:4"`
+	Addr     string `validate:"ip:v4|email"`
+	Name     string `validate:"!lower"`
+	Deadline *time.Time
+	Fax      string `validate:"required_with:Name Age"`
+	Prefix   string `validate:"prefix:ab"`
+}
+
+func TestFeedbackCode(t *testing.T) {
+	v := validator.New()
+	form := &codeForm{
+		Password: "abc",
+		Confirm:  "abcd",
+		Age:      16,
+		Pin:      "12345",
+		Addr:     "x",
+		Name:     "tom",
+		Prefix:   "cd",
+	}
+	err := v.Validate(form)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatalf("expected validation error: %v", err)
+	}
+	feedbacks := map[string][]*validator.Feedback{}
+	for _, fieldError := range e.Detail {
+		feedbacks[fieldError.Path] = fieldError.Feedbacks
+	}
+	cases := []struct {
+		path   string
+		code   string
+		params map[string]any
+	}{
+		{"Password", "len.range", map[string]any{"min": 6, "max": 18}},
+		{"Password", "password.strength", map[string]any{"level": 2}},
+		{"Confirm", "eq_field", map[string]any{"field": "Password"}},
+		{"Age", "gte", map[string]any{"value": int64(18)}},
+		{"Pin", "len.exact", map[string]any{"len": 4}},
+		{"Addr", "or", map[string]any{"alternatives": []string{"ip.v4", "email"}}},
+		{"Name", "not", map[string]any{"rule": "lower"}},
+		{"Fax", "required_with", map[string]any{"fields": []string{"Name", "Age"}}},
+		{"Prefix", "prefix", map[string]any{"prefix": "ab"}},
+	}
+	index := map[string]int{}
+	for _, c := range cases {
+		i := index[c.path]
+		index[c.path]++
+		if i >= len(feedbacks[c.path]) {
+			t.Errorf("missing feedback %d of '%s'", i, c.path)
+			continue
+		}
+		f := feedbacks[c.path][i]
+		if f.Code != c.code {
+			t.Errorf("unexpected code of '%s': %s", c.path, f.Code)
+		}
+		if !reflect.DeepEqual(f.Params, c.params) {
+			t.Errorf("unexpected params of '%s': %v", c.path, f.Params)
+		}
+	}
+	if f := feedbacks["Age"][0]; f.Value != 16 {
+		t.Errorf("unexpected value: %v", f.Value)
+	}
+	if f := feedbacks["Password"][0]; f.Value != "abc" {
+		t.Errorf("unexpected value: %v", f.Value)
+	}
+}
+
+func TestFeedbackDefaultCode(t *testing.T) {
+	v := validator.New()
+	v.RegisterValidator("img", func(v *validator.Validation) error {
+		return v.Error("invalid image")
+	})
+	var s *string
+	e, ok := v.Var(s, "required").(*validator.FieldError)
+	if !ok {
+		t.Fatal("expected field error")
+	}
+	if f := e.Feedbacks[0]; f.Code != "required" || f.Params != nil || f.Value != nil {
+		t.Errorf("unexpected feedback: %s %v %v", f.Code, f.Params, f.Value)
+	}
+	e, ok = v.Var("a.bmp", "img:png").(*validator.FieldError)
+	if !ok {
+		t.Fatal("expected field error")
+	}
+	if f := e.Feedbacks[0]; f.Code != "img" || f.Params["param"] != "png" || f.Value != "a.bmp" {
+		t.Errorf("unexpected feedback: %s %v %v", f.Code, f.Params, f.Value)
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ConcurrentAddress struct {
+	Domain string `validate:"mx"`
+}
+
+type ConcurrentUser struct {
+	Name     string   `validate:"required,unique,len:2-10"`
+	Email    string   `validate:"unique|blank_email"`
+	Nickname string   `validate:"!unique"`
+	Tags     []string `validate:"dive,unique"`
+	Address  ConcurrentAddress
+	Domains  map[string]string `validate:"dive,mx"`
+}
+
+type ConcurrentContact struct {
+	Email string `validate:"blank_email|unique"`
+}
+
+// pool counts the validators running at the same time
+type pool struct {
+	lock    sync.Mutex
+	running int
+	max     int
+	calls   int
+}
+
+func (self *pool) enter() {
+	self.lock.Lock()
+	self.running++
+	self.calls++
+	if self.running > self.max {
+		self.max = self.running
+	}
+	self.lock.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	self.lock.Lock()
+	self.running--
+	self.lock.Unlock()
+}
+
+func newConcurrentEngine(p *pool) *validator.Engine {
+	taken := map[string]bool{"tom": true, "go": true, "tom@example.com": true}
+	v := validator.New()
+	v.RegisterExpensiveValidator("unique", func(v *validator.Validation) error {
+		p.enter()
+		if taken[v.Field.String()] {
+			return v.Error("already taken")
+		}
+		return nil
+	})
+	v.RegisterExpensiveValidator("mx", func(v *validator.Validation) error {
+		p.enter()
+		if v.Field.String() != "example.com" {
+			return v.Errorf("no mx record for '%s'", v.Field.String())
+		}
+		return nil
+	})
+	v.RegisterValidator("blank_email", func(v *validator.Validation) error {
+		if v.Field.String() != "" {
+			return v.Error("must be blank")
+		}
+		return nil
+	})
+	return v
+}
+
+func TestConcurrency(t *testing.T) {
+	user := ConcurrentUser{
+		Name:     "tom",
+		Email:    "tom@example.com",
+		Nickname: "jerry",
+		Tags:     []string{"go", "rust", "go"},
+		Address:  ConcurrentAddress{Domain: "example.org"},
+		Domains:  map[string]string{"a": "example.com", "b": "example.net"},
+	}
+	sequential := &pool{}
+	expected, _ := json.Marshal(newConcurrentEngine(sequential).Validate(&user))
+	concurrent := &pool{}
+	v := newConcurrentEngine(concurrent)
+	v.SetConcurrency(3)
+	err := v.Validate(&user)
+	if _, ok := err.(*validator.ValidationError); !ok {
+		t.Fatal(err)
+	}
+	result, _ := json.Marshal(err)
+	if string(result) != string(expected) {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+	if concurrent.calls != sequential.calls || concurrent.calls != 9 {
+		t.Errorf("expected %d calls, got %d", sequential.calls, concurrent.calls)
+	}
+	if sequential.max != 1 || concurrent.max < 2 || concurrent.max > 3 {
+		t.Errorf("unexpected concurrency: sequential %d, concurrent %d", sequential.max, concurrent.max)
+	}
+	// the limits apply to the feedbacks in declaration order
+	v.SetFailFast(true)
+	e, ok := v.Validate(&user).(*validator.ValidationError)
+	if !ok || len(e.Detail) != 1 || e.Detail[0].Path != "Name" || !e.Truncated {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
+
+func TestConcurrencyPanic(t *testing.T) {
+	v := validator.New()
+	v.RegisterExpensiveValidator("unique", func(v *validator.Validation) error {
+		panic("lookup failed")
+	})
+	v.SetConcurrency(2)
+	defer func() {
+		if r := recover(); r != "lookup failed" {
+			t.Errorf("expected the panic of the validator, got %v", r)
+		}
+	}()
+	v.Validate(&ContextUser{Name: "tom", Email: "tom@example.com"})
+}
+
+func TestConcurrencyOr(t *testing.T) {
+	sequential := &pool{}
+	if err := newConcurrentEngine(sequential).Validate(&ConcurrentContact{}); err != nil {
+		t.Fatal(err)
+	}
+	concurrent := &pool{}
+	v := newConcurrentEngine(concurrent)
+	v.SetConcurrency(2)
+	if err := v.Validate(&ConcurrentContact{}); err != nil {
+		t.Fatal(err)
+	}
+	// the expensive alternative runs ahead even though the one before it passes
+	if sequential.calls != 0 || concurrent.calls != 1 {
+		t.Errorf("unexpected calls: sequential %d, concurrent %d", sequential.calls, concurrent.calls)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"testing"
+)
+
+type companyForm struct {
+	Type      string
+	Verified  bool
+	TaxID     string `validate:"required_if:Type business Verified true"`
+	IDCard    string `validate:"required_unless:Type business"`
+	Phone     string `validate:"required_without:Email"`
+	Email     string
+	Fax       string `validate:"excluded_with:Phone Email"`
+	Address   string `validate:"required_with:Fax"`
+	Nickname  string `validate:"excluded_if:Type business"`
+	LegalName string `validate:"excluded_unless:Type business"`
+}
+
+func TestConditional(t *testing.T) {
+	v := validator.New()
+	form := &companyForm{
+		Type:      "business",
+		Verified:  true,
+		Fax:       "123",
+		Nickname:  "nick",
+		LegalName: "ACME",
+	}
+	e, ok := v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	m := e.Map()
+	expected := map[string]string{
+		"TaxID":    "field is required when Type is business and Verified is true",
+		"Phone":    "field is required when Email is not present",
+		"Address":  "field is required when Fax is present",
+		"Nickname": "field must be empty when Type is business",
+	}
+	if len(m) != len(expected) {
+		t.Errorf("unexpected errors: %v", m)
+	}
+	for k, s := range expected {
+		if m[k] != s {
+			t.Errorf("unexpected error of '%s': %v", k, m[k])
+		}
+	}
+	e.SetTranslation(hans.New())
+	if s := e.Map()["TaxID"]; s != "当Type为business且Verified为true时该字段是必填的" {
+		t.Errorf("unexpected translation: %s", s)
+	}
+
+	form = &companyForm{
+		Type:      "person",
+		IDCard:    "110",
+		Email:     "a@b.com",
+		LegalName: "",
+	}
+	if err := v.Validate(form); err != nil {
+		t.Error(err)
+	}
+	form.Fax = "123"
+	form.Address = "street"
+	form.LegalName = "ACME"
+	e, ok = v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if m := e.Map(); len(m) != 2 || m["Fax"] == "" || m["LegalName"] == "" {
+		t.Errorf("unexpected errors: %v", m)
+	}
+}
+
+type conditionInvalidForm struct {
+	A string `validate:"required_if:Missing x"`
+}
+
+func TestConditionalInvalidParam(t *testing.T) {
+	v := validator.New()
+	if _, ok := v.Validate(&conditionInvalidForm{}).(*validator.ValidationError); ok {
+		t.Error("expected param error")
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+type tenantKey struct{}
+
+type ContextUser struct {
+	Name  string   `validate:"unique"`
+	Email string   `validate:"unique"`
+	Tags  []string `validate:"dive,unique"`
+}
+
+func TestValidateCtx(t *testing.T) {
+	taken := map[string]map[string]bool{
+		"acme": {"tom": true},
+	}
+	var calls int
+	v := validator.New()
+	v.RegisterValidator("unique", func(v *validator.Validation) error {
+		calls++
+		tenant, _ := v.Context().Value(tenantKey{}).(string)
+		if taken[tenant][v.Field.String()] {
+			return v.Error("already taken")
+		}
+		return nil
+	})
+	user := ContextUser{Name: "tom", Email: "tom@example.com", Tags: []string{"a", "b"}}
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	err := v.ValidateCtx(ctx, &user)
+	e, ok := err.(*validator.ValidationError)
+	if !ok || len(e.Detail) != 1 || e.Detail[0].Path != "Name" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the validators get a background context from Validate
+	if err := v.Validate(&user); err != nil {
+		t.Fatal(err)
+	}
+	// the validation stops at the next field once the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	v.RegisterValidator("unique", func(v *validator.Validation) error {
+		calls++
+		cancel()
+		return nil
+	})
+	if err := v.ValidateCtx(ctx, &user); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
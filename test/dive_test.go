@@ -0,0 +1,55 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+type diveItem struct {
+	Name string `validate:"required"`
+}
+
+type diveForm struct {
+	Emails []string          `validate:"len:1-3,dive,email"`
+	Limits map[string]int    `validate:"dive,keys,len:2-4,endkeys,gte:0"`
+	Items  []diveItem        `validate:"dive"`
+	Matrix [][]int           `validate:"dive,len:2,dive,lt:10"`
+	Tags   map[string]string `validate:"blank,dive,required"`
+}
+
+func TestDive(t *testing.T) {
+	form := &diveForm{
+		Emails: []string{"a@b.com", "bad"},
+		Limits: map[string]int{"cpu": -1, "m": 1, "disk": 2},
+		Items:  []diveItem{{Name: "a"}, {}},
+		Matrix: [][]int{{1, 2}, {3}, {4, 12}},
+	}
+	v := validator.New()
+	err := v.Validate(form)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	m := e.Map()
+	for _, path := range []string{"Emails[1]", "Limits[cpu]", "Limits[m]", "Items[1].Name", "Matrix[1]", "Matrix[2][1]"} {
+		if _, ok := m[path]; !ok {
+			t.Errorf("missing error for '%s': %v", path, m)
+		}
+		delete(m, path)
+	}
+	if len(m) > 0 {
+		t.Errorf("unexpected errors: %v", m)
+	}
+}
+
+type diveKeysForm struct {
+	Slice []string `validate:"dive,keys,required,endkeys"`
+}
+
+func TestDiveInvalid(t *testing.T) {
+	v := validator.New()
+	err := v.Validate(&diveKeysForm{Slice: []string{"a"}})
+	if _, ok := err.(*validator.ValidationError); ok || err == nil {
+		t.Errorf("expected config error, got %v", err)
+	}
+}
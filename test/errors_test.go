@@ -0,0 +1,73 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+func TestErrorsAs(t *testing.T) {
+	v := validator.New()
+	err := v.Validate(&codeForm{Password: "abc"})
+	var fieldErr *validator.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != "Password" {
+		t.Fatalf("expected field error of Password: %v", fieldErr)
+	}
+	var feedback *validator.Feedback
+	if !errors.As(err, &feedback) || feedback.Code != "len.range" {
+		t.Errorf("expected feedback len.range: %v", feedback)
+	}
+	var configErr *validator.ConfigError
+	if errors.As(err, &configErr) {
+		t.Errorf("unexpected config error: %v", configErr)
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	type unregistered struct {
+		Name string `validate:"requred"`
+	}
+	type invalidParam struct {
+		Age int `validate:"gt:abc"`
+	}
+	type unsupportedType struct {
+		Age int `validate:"lower"`
+	}
+	type invalidTag struct {
+		Name string `validate:"'required"`
+	}
+	type badDive struct {
+		Name string `validate:"dive,required"`
+	}
+	cases := []struct {
+		value  interface{}
+		target error
+	}{
+		{&unregistered{}, validator.ErrUnregisteredValidator},
+		{&invalidParam{Age: 1}, validator.ErrInvalidParam},
+		{&unsupportedType{Age: 1}, validator.ErrUnsupportedType},
+		{&invalidTag{}, validator.ErrInvalidTag},
+		{&badDive{}, validator.ErrUnsupportedType},
+		{(*companyForm)(nil), validator.ErrInvalidValue},
+		{1, validator.ErrInvalidValue},
+	}
+	v := validator.New()
+	for _, c := range cases {
+		err := v.Validate(c.value)
+		if !errors.Is(err, c.target) {
+			t.Errorf("%T: expected %v, got %v", c.value, c.target, err)
+		}
+		var configErr *validator.ConfigError
+		if !errors.As(err, &configErr) {
+			t.Errorf("%T: expected config error, got %v", c.value, err)
+		}
+	}
+	err := v.Var(1, "gt:x")
+	var configErr *validator.ConfigError
+	if !errors.As(err, &configErr) || configErr.Flag != "gt" || !errors.Is(err, validator.ErrInvalidParam) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err.Error() == "" {
+		t.Error("expected message")
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+type failFastForm struct {
+	A string   `validate:"required,len:3"`
+	B string   `validate:"required,email"`
+	C []string `validate:"dive,required"`
+	D string   `validate:"required"`
+}
+
+func countFeedbacks(e *validator.ValidationError) int {
+	n := 0
+	for _, fe := range e.Detail {
+		n += len(fe.Feedbacks)
+	}
+	return n
+}
+
+func TestFailFast(t *testing.T) {
+	form := &failFastForm{C: []string{"", ""}}
+
+	v := validator.New()
+	e, ok := v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if e.Truncated || len(e.Detail) != 5 || countFeedbacks(e) != 7 {
+		t.Errorf("unexpected result: %v %d", e, countFeedbacks(e))
+	}
+
+	v.SetFailFast(true)
+	e, ok = v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if !e.Truncated || len(e.Detail) != 1 || e.Detail[0].Path != "A" || countFeedbacks(e) != 2 {
+		t.Errorf("unexpected fail fast result: %v", e)
+	}
+
+	v = validator.New()
+	v.SetFieldFailFast(true)
+	e, ok = v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if !e.Truncated || len(e.Detail) != 5 || countFeedbacks(e) != 5 {
+		t.Errorf("unexpected field fail fast result: %v", e)
+	}
+
+	v = validator.New()
+	v.SetMaxErrors(5)
+	e, ok = v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if !e.Truncated || countFeedbacks(e) != 5 || e.Detail[len(e.Detail)-1].Path != "C[0]" {
+		t.Errorf("unexpected max errors result: %v", e)
+	}
+
+	v.SetMaxErrors(7)
+	e, ok = v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if e.Truncated || countFeedbacks(e) != 7 {
+		t.Errorf("unexpected max errors result: %v", e)
+	}
+}
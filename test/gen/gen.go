@@ -0,0 +1,51 @@
+// Package gen holds the types of the validatorgen tests, their Validate methods are generated
+package gen
+
+import "time"
+
+//go:generate go -C ../../cmd run ./validatorgen -type User,Address,Base,Order -output validator_gen.go ../test/gen
+
+type Level int
+
+type Base struct {
+	ID int64 `validate:"gt:0"`
+}
+
+type Address struct {
+	City string `validate:"required"`
+	Zip  string `validate:"blank,number,len:6"`
+}
+
+type User struct {
+	Base
+	Name      string         `validate:"required,len:2-20,alpha"`
+	Email     string         `validate:"blank,email"`
+	Phone     *string        `validate:"required_without:Email"`
+	Password  string         `validate:"password:2"`
+	Confirm   string         `validate:"eq_field:Password"`
+	Age       uint8          `validate:"gte:18,lte:130"`
+	Score     float32        `validate:"blank,gt:0.5"`
+	Level     Level          `validate:"lt:10"`
+	Website   string         `validate:"blank,prefix:'https://'"`
+	Role      string         `validate:"required"`
+	Admin     string         `validate:"required_if:Role admin"`
+	Guest     string         `validate:"excluded_unless:Role guest"`
+	Birthday  time.Time      `validate:"required"`
+	Tags      []string       `validate:"len:1-5,dive,lower,len:1-10"`
+	Limits    map[string]int `validate:"dive,keys,len:2-8,endkeys,gte:0"`
+	Matrix    [][]int        `validate:"dive,dive,lt:10"`
+	Address   Address
+	Shipping  *Address
+	Addresses []Address       `validate:"dive"`
+	Labels    map[int]*string `validate:"dive,required"`
+	note      string          `validate:"required"`
+	Ignored   string          `validate:"-"`
+}
+
+type Order struct {
+	Number string  `validate:"required,upper"`
+	Amount float64 `validate:"gt:0"`
+	Min    *int    `validate:"blank,lte_field:Max"`
+	Max    int
+	Items  []*Base `validate:"len:1-9,dive"`
+}
@@ -0,0 +1,194 @@
+// Code generated by validatorgen; DO NOT EDIT.
+
+package gen
+
+import (
+	"github.com/shaopson/validator"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ValidatorGenerated marks the Validate method of User as generated, see validator.Generated
+func (*User) ValidatorGenerated() {}
+
+// Validate validates the validate tags of User
+func (self *User) Validate() error {
+	r := validator.NewFailures(self)
+	r.Merge("", self.Base.Validate())
+	if self.Name == "" {
+		r.Fail("Name", "Name", self.Name, "required")
+	}
+	if len(self.Name) < 2 || len(self.Name) > 20 {
+		r.Fail("Name", "Name", self.Name, "len:2-20")
+	}
+	if !validator.MatchString("alpha", "", self.Name) {
+		r.Fail("Name", "Name", self.Name, "alpha")
+	}
+	if self.Email != "" {
+		if !validator.MatchString("email", "", self.Email) {
+			r.Fail("Email", "Email", self.Email, "email")
+		}
+	}
+	if self.Phone == nil && (self.Email == "") {
+		r.Fail("Phone", "Phone", self.Phone, "required_without:Email")
+	}
+	if !validator.MatchString("password", "2", self.Password) {
+		r.Fail("Password", "Password", self.Password, "password:2")
+	}
+	if !(self.Confirm == self.Password) {
+		r.Fail("Confirm", "Confirm", self.Confirm, "eq_field:Password")
+	}
+	if !(uint64(self.Age) >= 18) {
+		r.Fail("Age", "Age", self.Age, "gte:18")
+	}
+	if !(uint64(self.Age) <= 130) {
+		r.Fail("Age", "Age", self.Age, "lte:130")
+	}
+	if self.Score != 0 {
+		if !(float64(self.Score) > 0.5) {
+			r.Fail("Score", "Score", self.Score, "gt:0.5")
+		}
+	}
+	if !(int64(self.Level) < 10) {
+		r.Fail("Level", "Level", self.Level, "lt:10")
+	}
+	if self.Website != "" {
+		if !validator.MatchString("prefix", "https://", self.Website) {
+			r.Fail("Website", "Website", self.Website, "prefix:'https://'")
+		}
+	}
+	if self.Role == "" {
+		r.Fail("Role", "Role", self.Role, "required")
+	}
+	if self.Admin == "" && (self.Role == "admin") {
+		r.Fail("Admin", "Admin", self.Admin, "required_if:'Role admin'")
+	}
+	if self.Guest != "" && !(self.Role == "guest") {
+		r.Fail("Guest", "Guest", self.Guest, "excluded_unless:'Role guest'")
+	}
+	if self.Birthday == (time.Time{}) {
+		r.Fail("Birthday", "Birthday", self.Birthday, "required")
+	}
+	if len(self.Tags) < 1 || len(self.Tags) > 5 {
+		r.Fail("Tags", "Tags", self.Tags, "len:1-5")
+	}
+	for i0, e0 := range self.Tags {
+		p0 := "Tags[" + strconv.Itoa(i0) + "]"
+		if !validator.MatchString("lower", "", e0) {
+			r.Fail(p0, "Tags", e0, "lower")
+		}
+		if len(e0) < 1 || len(e0) > 10 {
+			r.Fail(p0, "Tags", e0, "len:1-10")
+		}
+	}
+	k1s := make([]string, 0, len(self.Limits))
+	for k1 := range self.Limits {
+		k1s = append(k1s, k1)
+	}
+	sort.Slice(k1s, func(a, b int) bool { return k1s[a] < k1s[b] })
+	for _, k1 := range k1s {
+		p1 := "Limits[" + k1 + "]"
+		if len(k1) < 2 || len(k1) > 8 {
+			r.Fail(p1, "Limits", k1, "len:2-8")
+		}
+		e1 := self.Limits[k1]
+		if !(int64(e1) >= 0) {
+			r.Fail(p1, "Limits", e1, "gte:0")
+		}
+	}
+	for i2, e2 := range self.Matrix {
+		p2 := "Matrix[" + strconv.Itoa(i2) + "]"
+		for i3, e3 := range e2 {
+			p3 := p2 + "[" + strconv.Itoa(i3) + "]"
+			if !(int64(e3) < 10) {
+				r.Fail(p3, "Matrix", e3, "lt:10")
+			}
+		}
+	}
+	r.Merge("Address", self.Address.Validate())
+	if self.Shipping != nil {
+		r.Merge("Shipping", self.Shipping.Validate())
+	}
+	for i4, e4 := range self.Addresses {
+		p4 := "Addresses[" + strconv.Itoa(i4) + "]"
+		r.Merge(p4, e4.Validate())
+	}
+	k5s := make([]int, 0, len(self.Labels))
+	for k5 := range self.Labels {
+		k5s = append(k5s, k5)
+	}
+	sort.Slice(k5s, func(a, b int) bool { return k5s[a] < k5s[b] })
+	for _, k5 := range k5s {
+		p5 := "Labels[" + strconv.FormatInt(int64(k5), 10) + "]"
+		e5 := self.Labels[k5]
+		if e5 == nil {
+			r.Fail(p5, "Labels", e5, "required")
+		}
+	}
+	return r.Err()
+}
+
+// ValidatorGenerated marks the Validate method of Address as generated, see validator.Generated
+func (*Address) ValidatorGenerated() {}
+
+// Validate validates the validate tags of Address
+func (self *Address) Validate() error {
+	r := validator.NewFailures(self)
+	if self.City == "" {
+		r.Fail("City", "City", self.City, "required")
+	}
+	if self.Zip != "" {
+		if !validator.MatchString("number", "", self.Zip) {
+			r.Fail("Zip", "Zip", self.Zip, "number")
+		}
+		if len(self.Zip) != 6 {
+			r.Fail("Zip", "Zip", self.Zip, "len:6")
+		}
+	}
+	return r.Err()
+}
+
+// ValidatorGenerated marks the Validate method of Base as generated, see validator.Generated
+func (*Base) ValidatorGenerated() {}
+
+// Validate validates the validate tags of Base
+func (self *Base) Validate() error {
+	r := validator.NewFailures(self)
+	if !(int64(self.ID) > 0) {
+		r.Fail("ID", "ID", self.ID, "gt:0")
+	}
+	return r.Err()
+}
+
+// ValidatorGenerated marks the Validate method of Order as generated, see validator.Generated
+func (*Order) ValidatorGenerated() {}
+
+// Validate validates the validate tags of Order
+func (self *Order) Validate() error {
+	r := validator.NewFailures(self)
+	if self.Number == "" {
+		r.Fail("Number", "Number", self.Number, "required")
+	}
+	if !validator.MatchString("upper", "", self.Number) {
+		r.Fail("Number", "Number", self.Number, "upper")
+	}
+	if !(float64(self.Amount) > 0) {
+		r.Fail("Amount", "Amount", self.Amount, "gt:0")
+	}
+	if self.Min != nil {
+		if !(int64(*self.Min) <= int64(self.Max)) {
+			r.Fail("Min", "Min", self.Min, "lte_field:Max")
+		}
+	}
+	if len(self.Items) < 1 || len(self.Items) > 9 {
+		r.Fail("Items", "Items", self.Items, "len:1-9")
+	}
+	for i0, e0 := range self.Items {
+		p0 := "Items[" + strconv.Itoa(i0) + "]"
+		if e0 != nil {
+			r.Merge(p0, e0.Validate())
+		}
+	}
+	return r.Err()
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/test/gen"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validGenUser() *gen.User {
+	phone := "13800138000"
+	label := "a"
+	return &gen.User{
+		Base:      gen.Base{ID: 1},
+		Name:      "Tom",
+		Phone:     &phone,
+		Password:  "Abc123",
+		Confirm:   "Abc123",
+		Age:       20,
+		Role:      "guest",
+		Guest:     "yes",
+		Birthday:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:      []string{"go"},
+		Limits:    map[string]int{"ab": 1},
+		Matrix:    [][]int{{1, 2}},
+		Address:   gen.Address{City: "Beijing", Zip: "100000"},
+		Addresses: []gen.Address{{City: "Shanghai"}},
+		Labels:    map[int]*string{1: &label},
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	min := 5
+	invalid := &gen.User{
+		Name:      "T0m",
+		Email:     "tom",
+		Password:  "abc",
+		Confirm:   "abd",
+		Age:       16,
+		Score:     0.2,
+		Level:     12,
+		Website:   "http://a.com",
+		Role:      "admin",
+		Guest:     "yes",
+		Tags:      []string{"Go", ""},
+		Limits:    map[string]int{"b": -1, "abc": 2, "a": -2},
+		Matrix:    [][]int{{1, 20}, {30}},
+		Address:   gen.Address{Zip: "12"},
+		Shipping:  &gen.Address{Zip: "abcdef"},
+		Addresses: []gen.Address{{}, {City: "Shanghai", Zip: "1"}},
+		Labels:    map[int]*string{3: nil, 1: nil},
+	}
+	forms := []interface{}{
+		validGenUser(),
+		invalid,
+		&gen.User{},
+		&gen.Order{},
+		&gen.Order{Number: "a1", Amount: -1, Min: &min, Max: 3, Items: []*gen.Base{nil, {}}},
+		&gen.Order{Number: "A1", Amount: 1, Min: &min, Max: 5, Items: []*gen.Base{{ID: 1}}},
+	}
+	v := validator.New()
+	for i, form := range forms {
+		expected, err := json.Marshal(v.Validate(form))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := json.Marshal(form.(validator.Generated).Validate())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != string(expected) {
+			t.Errorf("form %d: generated errors differ\nexpected: %s\nactual:   %s", i, expected, actual)
+		}
+	}
+	if err := validGenUser().Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the generator")
+	}
+	output := filepath.Join(t.TempDir(), "validator_gen.go")
+	dir, err := filepath.Abs("gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// validatorgen is built in the module of cmd
+	cmd := exec.Command("go", "-C", "../cmd", "run", "./validatorgen", "-type", "User,Address,Base,Order", "-output", output, dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+	expected, err := os.ReadFile(filepath.Join("gen", "validator_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != string(expected) {
+		t.Error("gen/validator_gen.go is out of date, run go generate ./test/gen")
+	}
+}
+
+func TestGeneratedOtherPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the generator")
+	}
+	dir, err := filepath.Abs(filepath.Join("testdata", "genext"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "validator_gen.go")
+	cmd := exec.Command("go", "-C", "../cmd", "run", "./validatorgen", "-type", "User", "-output", output, dir)
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "User.Addr: nested type 'gen.Address' of another package is not supported") {
+		t.Errorf("expected the nested struct of another package to be rejected, got %v: %s", err, out)
+	}
+}
+
+func TestGeneratedDisagree(t *testing.T) {
+	address := &gen.Address{City: "Paris"}
+	r := validator.NewFailures(address)
+	r.Fail("City", "City", address.City, "required")
+	var configErr *validator.ConfigError
+	if err := r.Err(); !errors.As(err, &configErr) || configErr.Field != "City" {
+		t.Errorf("expected a config error for the passing value, got %v", err)
+	}
+}
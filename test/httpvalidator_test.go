@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"github.com/shaopson/validator/httpvalidator"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signupRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"len:6-18"`
+}
+
+func newSignupServer() http.Handler {
+	v := validator.New()
+	v.RegisterTagNameFunc(validator.TagName("json"))
+	m := httpvalidator.New(v)
+	m.RegisterTranslation("zh", hans.New())
+	return m.Handler(signupRequest{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := httpvalidator.Value(r).(*signupRequest)
+		w.Write([]byte(req.Email))
+	}))
+}
+
+func serveSignup(body string, lang string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	if lang != "" {
+		r.Header.Set("Accept-Language", lang)
+	}
+	w := httptest.NewRecorder()
+	newSignupServer().ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	w := serveSignup(`{"email":"a@b.com","password":"secret"}`, "")
+	if w.Code != http.StatusOK || w.Body.String() != "a@b.com" {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body)
+	}
+
+	w = serveSignup(`{"email":`, "")
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != httpvalidator.ContentType {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	w = serveSignup(`{"email":"x","password":"abc"}`, "")
+	if w.Code != http.StatusUnprocessableEntity || w.Header().Get("Content-Type") != httpvalidator.ContentType {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	var p httpvalidator.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != http.StatusUnprocessableEntity || p.Title != "Unprocessable Entity" || len(p.InvalidParams) != 2 {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+	if param := p.InvalidParams[1]; param.Name != "password" || param.Code != "len.range" || param.Reason != "field length must be 6-18 characters" {
+		t.Errorf("unexpected invalid param: %+v", param)
+	}
+}
+
+func TestMiddlewareLocale(t *testing.T) {
+	body := `{"email":"a@b.com"}`
+	cases := map[string]string{
+		"":                      "field length must be 6-18 characters",
+		"en-US,en;q=0.9":        "field length must be 6-18 characters",
+		"zh-CN,zh;q=0.9":        hans.New().Translate(lenFeedback()),
+		"fr;q=0.5, zh-TW;q=0.8": hans.New().Translate(lenFeedback()),
+		"en;q=0.1, zh;q=0":      "field length must be 6-18 characters",
+	}
+	for lang, reason := range cases {
+		w := serveSignup(body, lang)
+		var p httpvalidator.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		if len(p.InvalidParams) != 1 || p.InvalidParams[0].Reason != reason {
+			t.Errorf("unexpected problem of '%s': %+v", lang, p)
+		}
+	}
+}
+
+func lenFeedback() *validator.Feedback {
+	e := validator.New().Var("", "len:6-18").(*validator.FieldError)
+	return e.Feedbacks[0]
+}
+
+type brokenRequest struct {
+	Name string `json:"name" validate:"nope"`
+}
+
+func TestMiddlewareFailure(t *testing.T) {
+	m := httpvalidator.New(validator.New())
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	serve := func(h http.Handler, ctx context.Context) (*httptest.ResponseRecorder, httpvalidator.Problem) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"a@b.com","password":"secret","name":"x"}`)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		var p httpvalidator.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		return w, p
+	}
+
+	w, p := serve(m.Handler(brokenRequest{}, next), context.Background())
+	if w.Code != http.StatusInternalServerError || p.Status != w.Code || !strings.Contains(p.Detail, "nope") {
+		t.Errorf("unexpected response of a config error: %d %+v", w.Code, p)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	w, p = serve(m.Handler(signupRequest{}, next), canceled)
+	if w.Code != httpvalidator.StatusClientClosedRequest || p.Title != "Client Closed Request" || p.Detail != context.Canceled.Error() {
+		t.Errorf("unexpected response of a canceled request: %d %+v", w.Code, p)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	w, _ = serve(m.Handler(signupRequest{}, next), expired)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected response of an expired request: %d", w.Code)
+	}
+
+	var handled error
+	m.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+	w, _ = serve(m.Handler(brokenRequest{}, next), context.Background())
+	var e *validator.ConfigError
+	if w.Code != http.StatusTeapot || !errors.As(handled, &e) {
+		t.Errorf("unexpected error handled: %d %v", w.Code, handled)
+	}
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"testing"
+)
+
+type jsonForm struct {
+	Password string `json:"password" validate:"len:6-18,password:2"`
+	Age      int    `json:"age" validate:"gte:18"`
+}
+
+func TestMarshalJSON(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(validator.TagName("json"))
+	err := v.Validate(&jsonForm{Password: "abc", Age: 16})
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := `[{"path":"password","field":"password","code":"len.range","message":"field length must be 6-18 characters","params":{"max":18,"min":6}},` +
+		`{"path":"password","field":"password","code":"password.strength","message":"password must contain uppercase and lowercase letters, numbers","params":{"level":2}},` +
+		`{"path":"age","field":"age","code":"gte","message":"field value must be greater than or equal to 18","params":{"value":18}}]`
+	if string(data) != expected {
+		t.Errorf("unexpected json: %s", data)
+	}
+
+	var decoded validator.ValidationError
+	if e := json.Unmarshal(data, &decoded); e != nil {
+		t.Fatal(e)
+	}
+	if decoded.Error() != err.Error() {
+		t.Errorf("unexpected error: %s", decoded.Error())
+	}
+	if len(decoded.Detail) != 2 || len(decoded.Detail[0].Feedbacks) != 2 {
+		t.Fatalf("unexpected detail: %v", decoded.Detail)
+	}
+	f := decoded.Detail[0].Feedbacks[0]
+	if f.Code != "len.range" || f.Validation.Flag != "len" || f.Params["min"] != float64(6) {
+		t.Errorf("unexpected feedback: %s %s %v", f.Code, f.Validation.Flag, f.Params)
+	}
+	again, _ := json.Marshal(&decoded)
+	if string(again) != expected {
+		t.Errorf("unexpected json: %s", again)
+	}
+
+	err.(*validator.ValidationError).SetTranslation(hans.New())
+	data, _ = json.Marshal(err.(*validator.ValidationError).Detail[1])
+	if string(data) != `[{"path":"age","field":"age","code":"gte","message":"field value must be greater than or equal to 18","params":{"value":18}}]` {
+		t.Errorf("unexpected json: %s", data)
+	}
+	data, _ = json.Marshal(err)
+	decoded = validator.ValidationError{}
+	json.Unmarshal(data, &decoded)
+	if decoded.Map()["age"] != err.(*validator.ValidationError).Map()["age"] {
+		t.Errorf("expected translated json: %s", data)
+	}
+}
+
+func TestMarshalJSONFeedback(t *testing.T) {
+	v := validator.New()
+	e := v.Var("", "required").(*validator.FieldError)
+	data, _ := json.Marshal(e.Feedbacks[0])
+	if string(data) != `{"path":"","field":"","code":"required","message":"field is required"}` {
+		t.Errorf("unexpected json: %s", data)
+	}
+	var f validator.Feedback
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Error() != "field is required" || f.Validation.Flag != "required" {
+		t.Errorf("unexpected feedback: %v", f)
+	}
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+func TestValidateMap(t *testing.T) {
+	var data map[string]any
+	payload := `{
+		"name": "T",
+		"email": "tom",
+		"password": "secret",
+		"confirm": "secreT",
+		"type": "business",
+		"address": {"city": ""},
+		"items": [{"price": 10, "name": "a"}, {"price": 0}, {"name": "c"}, {"price": 5}],
+		"tags": ["go", "Rust"],
+		"meta": null
+	}`
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		t.Fatal(err)
+	}
+	rules := map[string]string{
+		"name":          "required,len:2-20",
+		"email":         "blank,email",
+		"phone":         "blank,phone",
+		"confirm":       "eq_field:password",
+		"company":       "required_if:type business",
+		"address.city":  "required",
+		"address.zip":   "blank,len:6",
+		"items":         "len:1-5",
+		"items.*.price": "required,gt:0",
+		"items.*.name":  "required_with:price",
+		"tags.*":        "lower",
+		"meta":          "required",
+		"owner.id":      "required",
+	}
+	v := validator.New()
+	err := v.ValidateMap(data, rules)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	m := e.Map()
+	expected := map[string]string{
+		"address.city":   "field is required",
+		"company":        "field is required when type is business",
+		"confirm":        "field must be equal to field 'password'",
+		"email":          "invalid email format",
+		"items[1].price": "field is required;field value must be greater than 0",
+		"items[2].price": "field is required",
+		"items[3].name":  "field is required when price is present",
+		"meta":           "field is required",
+		"name":           "field length must be 2-20 characters",
+		"owner.id":       "field is required",
+		"tags[1]":        "field must must be a lowercase string",
+	}
+	for path, message := range expected {
+		if m[path] != message {
+			t.Errorf("%s: expected '%s', got '%s'", path, message, m[path])
+		}
+		delete(m, path)
+	}
+	if len(m) > 0 {
+		t.Errorf("unexpected errors: %v", m)
+	}
+	if e.Detail[0].Path != "address.city" || e.Detail[0].Name != "city" {
+		t.Errorf("unexpected first error: %s %s", e.Detail[0].Path, e.Detail[0].Name)
+	}
+
+	valid := map[string]any{
+		"name":    "Tom",
+		"confirm": "secret", "password": "secret",
+		"type":    "personal",
+		"address": map[string]any{"city": "Beijing"},
+		"items":   []any{map[string]any{"price": 1.5, "name": "a"}},
+		"tags":    []string{"go"},
+		"meta":    map[string]any{},
+		"owner":   map[string]any{"id": 1},
+	}
+	if err := v.ValidateMap(valid, rules); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := v.ValidateMap(valid, map[string]string{"name": "unknown"}); err == nil {
+		t.Error("expected error for unregistered validator")
+	}
+}
+
+func TestValidateMapType(t *testing.T) {
+	var data map[string]any
+	payload := `{"email": 10, "name": true, "city": {"name": "Beijing"}, "tags": ["go", 1], "code": "42"}`
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		t.Fatal(err)
+	}
+	rules := map[string]string{
+		"email":  "required,email",
+		"name":   "len:2-20",
+		"city":   "lower|upper",
+		"tags.*": "alpha",
+		"code":   "alpha|number",
+	}
+	err := validator.New().ValidateMap(data, rules)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"email":   "number",
+		"name":    "boolean",
+		"city":    "object",
+		"tags[1]": "number",
+	}
+	if len(e.Detail) != len(expected) {
+		t.Errorf("unexpected errors: %v", e.Map())
+	}
+	for _, fe := range e.Detail {
+		f := fe.Feedbacks[0]
+		if len(fe.Feedbacks) != 1 || f.Code != "type" || f.Params["type"] != expected[fe.Path] {
+			t.Errorf("%s: unexpected feedbacks: %v", fe.Path, fe.Feedbacks)
+		}
+	}
+	if m := e.Map(); m["email"] != "invalid type 'number'" {
+		t.Errorf("unexpected message: %s", m["email"])
+	}
+}
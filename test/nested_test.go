@@ -0,0 +1,64 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+type nestedAddress struct {
+	City string `validate:"required"`
+	Zip  string `validate:"len:6,number"`
+}
+
+type nestedShipping struct {
+	Address  nestedAddress
+	Backup   *nestedAddress
+	Optional *nestedAddress `validate:"blank"`
+}
+
+type NestedBase struct {
+	ID int `validate:"gt:0"`
+}
+
+type nestedOrder struct {
+	NestedBase
+	Name     string `validate:"required"`
+	Shipping nestedShipping
+	Skipped  nestedAddress `validate:"-"`
+}
+
+func TestNested(t *testing.T) {
+	form := &nestedOrder{
+		Shipping: nestedShipping{
+			Address: nestedAddress{City: "Beijing", Zip: "12"},
+			Backup:  &nestedAddress{Zip: "100000"},
+		},
+	}
+	v := validator.New()
+	err := v.Validate(form)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	m := e.Map()
+	for _, path := range []string{"ID", "Name", "Shipping.Address.Zip", "Shipping.Backup.City"} {
+		if _, ok := m[path]; !ok {
+			t.Errorf("missing error for '%s': %v", path, m)
+		}
+		delete(m, path)
+	}
+	if len(m) > 0 {
+		t.Errorf("unexpected errors: %v", m)
+	}
+
+	form = &nestedOrder{
+		NestedBase: NestedBase{ID: 1},
+		Name:       "order",
+		Shipping: nestedShipping{
+			Address: nestedAddress{City: "Beijing", Zip: "100000"},
+		},
+	}
+	if err := v.Validate(form); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/openapi"
+	"github.com/shaopson/validator/schema"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type OpenAPIAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type OpenAPIUser struct {
+	Name      string           `json:"name" validate:"required,len:2-20"`
+	Password  string           `json:"password" validate:"password:1"`
+	Confirm   string           `json:"confirm" validate:"eq_field:Password"`
+	Code      string           `json:"code" validate:"blank,uuid"`
+	Addresses []OpenAPIAddress `json:"addresses" validate:"dive"`
+}
+
+func openAPIEngine() *validator.Engine {
+	engine := validator.New()
+	engine.RegisterValidator("uuid", func(v *validator.Validation) error {
+		return nil
+	})
+	engine.MustRegisterType(&OpenAPIUser{})
+	return engine
+}
+
+func TestOpenAPIYAML(t *testing.T) {
+	engine := openAPIEngine()
+	if types := engine.RegisteredTypes(); len(types) != 1 || types[0] != reflect.TypeOf(OpenAPIUser{}) {
+		t.Fatalf("unexpected registered types: %v", types)
+	}
+	components, err := openapi.New(engine).Components()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := components.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `components:
+  schemas:
+    OpenAPIAddress:
+      properties:
+        city:
+          minLength: 1
+          type: string
+      required:
+        - city
+      type: object
+    OpenAPIUser:
+      properties:
+        addresses:
+          items:
+            $ref: "#/components/schemas/OpenAPIAddress"
+          type: array
+        code:
+          anyOf:
+            - const: ""
+            - x-uuid: true
+          type: string
+        confirm:
+          type: string
+          x-eq_field: password
+        name:
+          maxLength: 20
+          minLength: 2
+          type: string
+        password:
+          pattern: "^(?=.*[a-zA-Z]+)(?=.*\\d+)"
+          type: string
+          x-password: 1
+      required:
+        - name
+      type: object
+`
+	if string(data) != expected {
+		t.Errorf("unexpected yaml:\n%s", data)
+	}
+}
+
+func TestOpenAPIJSON(t *testing.T) {
+	generator := openapi.New(openAPIEngine())
+	generator.Schema.RegisterFunc("uuid", func(s schema.Schema, flag validator.Flag, typ reflect.Type) error {
+		s["format"] = "uuid"
+		return nil
+	})
+	components, err := generator.Components()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := components.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	code := doc.Components.Schemas["OpenAPIUser"]["properties"].(map[string]interface{})["code"]
+	expected := map[string]interface{}{"type": "string", "anyOf": []interface{}{map[string]interface{}{"const": ""}, map[string]interface{}{"format": "uuid"}}}
+	if !reflect.DeepEqual(code, expected) {
+		t.Errorf("unexpected schema: %v", code)
+	}
+}
+
+func TestOpenAPILoadedRules(t *testing.T) {
+	engine := openAPIEngine()
+	err := engine.RegisterRules(validator.Rules[OpenAPIAddress]().Field("City", validator.Required(), validator.Lower()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	components, err := openapi.New(engine).Components()
+	if err != nil {
+		t.Fatal(err)
+	}
+	city := components.Schemas["OpenAPIAddress"].(schema.Schema)["properties"].(schema.Schema)["city"]
+	expected := schema.Schema{"type": "string", "minLength": 1, "pattern": "^[^A-Z]*$"}
+	if !reflect.DeepEqual(city, expected) {
+		t.Errorf("unexpected schema: %v", city)
+	}
+}
+
+type OpenAPIQuoted struct {
+	Answer string `json:"answer" validate:"eq:yes"`
+	Note   string `json:"note" validate:"eq:a #b: c"`
+	Code   string `json:"code" validate:"eq:0x1"`
+}
+
+func TestOpenAPIYAMLQuote(t *testing.T) {
+	engine := validator.New()
+	engine.MustRegisterType(&OpenAPIQuoted{})
+	components, err := openapi.New(engine).Components()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := components.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`const: "yes"`,
+		`const: "a #b: c"`,
+		`const: "0x1"`,
+	} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("missing '%s' in:\n%s", line, data)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"sync"
+	"testing"
+	"time"
+)
+
+type planForm struct {
+	UserName  string    `validate:"required,len:6-18,username"`
+	Password  string    `validate:"required,len:8-20,password:2"`
+	Password2 string    `validate:"eq_field:Password"`
+	Age       int       `validate:"gte:18,lt:120"`
+	Score     float64   `validate:"gt:0.5"`
+	BirthDay  time.Time `validate:"gt:1900-01-01"`
+	Email     string    `validate:"blank,email"`
+	Tags      []string  `validate:"dive,len:1-10"`
+}
+
+func newPlanForm() *planForm {
+	birthday, _ := time.Parse("2006-01-02", "1990-10-10")
+	return &planForm{
+		UserName:  "jack.ma",
+		Password:  "Abc12345",
+		Password2: "Abc12345",
+		Age:       30,
+		Score:     1.5,
+		BirthDay:  birthday,
+		Email:     "jack@gmail.com",
+		Tags:      []string{"a", "b"},
+	}
+}
+
+func TestConcurrentValidate(t *testing.T) {
+	v := validator.New()
+	valid := newPlanForm()
+	invalid := newPlanForm()
+	invalid.Age = 10
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := v.Validate(valid); err != nil {
+					t.Error(err)
+				}
+				if _, ok := v.Validate(invalid).(*validator.ValidationError); !ok {
+					t.Error("expected validation error")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type planCustomForm struct {
+	Img string `validate:"img"`
+}
+
+func TestRegisterAfterValidate(t *testing.T) {
+	v := validator.New()
+	form := &planCustomForm{Img: "a.txt"}
+	if _, ok := v.Validate(form).(*validator.ValidationError); ok {
+		t.Fatal("expected unregistered validator error")
+	}
+	v.RegisterValidator("img", func(v *validator.Validation) error {
+		return v.Error("must be image file")
+	})
+	if _, ok := v.Validate(form).(*validator.ValidationError); !ok {
+		t.Fatal("expected validation error")
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	v := validator.New()
+	form := newPlanForm()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := v.Validate(form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkValidateUncompiled uses a new Engine every time, so the tags are compiled on every call
+func BenchmarkValidateUncompiled(b *testing.B) {
+	form := newPlanForm()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := validator.New().Validate(form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	v := validator.New()
+	form := newPlanForm()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := v.Validate(form); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
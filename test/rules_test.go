@@ -0,0 +1,228 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type RulesAddress struct {
+	City string `validate:"required"`
+	Zip  string
+}
+
+type RulesUser struct {
+	Name    string `validate:"required,len:2-5"`
+	Email   string `validate:"email"`
+	Age     int    `validate:"-"`
+	Tags    []string
+	Address RulesAddress
+}
+
+type RulesContact struct {
+	Contact string `validate:"required,phone|email"`
+}
+
+func TestLoadRulesJSON(t *testing.T) {
+	v := validator.New()
+	v.MustRegisterType(&RulesUser{})
+	doc := `{
+		"RulesUser": {
+			"Name": "len:2-20",
+			"Age": "gte:18",
+			"Tags": "len:1-3,dive,lower",
+			"Address.Zip": "required,len:6"
+		}
+	}`
+	if err := v.LoadRules(strings.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+	user := RulesUser{Name: "Jonathan", Email: "tom", Age: 16, Tags: []string{"go", "Go"}}
+	err := v.Validate(&user)
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Email":        "invalid email format",
+		"Age":          "field value must be greater than or equal to 18",
+		"Tags[1]":      "field must must be a lowercase string",
+		"Address.City": "field is required",
+		"Address.Zip":  "field is required;field length must be 6 characters",
+	}
+	m := e.Map()
+	for path, message := range expected {
+		if m[path] != message {
+			t.Errorf("%s: expected '%s', got '%s'", path, message, m[path])
+		}
+	}
+	if len(m) != len(expected) {
+		t.Errorf("unexpected errors: %v", m)
+	}
+}
+
+func TestLoadRulesYAML(t *testing.T) {
+	v := validator.New()
+	v.MustRegisterType(&RulesUser{}, &RulesAddress{})
+	doc := `
+# the rules merged with the tags
+RulesUser:
+  Name: "+len:2-10"   # keeps required
+  Email: '+blank'
+RulesAddress:
+  City: +lower
+  Zip: '!eq:0'
+`
+	if err := v.LoadRules(strings.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		user     RulesUser
+		expected map[string]string
+	}{
+		{RulesUser{Name: "Jonathan", Address: RulesAddress{City: "paris"}}, nil},
+		{RulesUser{Email: "tom", Address: RulesAddress{City: "Paris"}}, map[string]string{
+			"Name":         "field is required;field length must be 2-10 characters",
+			"Email":        "invalid email format",
+			"Address.City": "field must must be a lowercase string",
+		}},
+	}
+	for i, c := range cases {
+		err := v.Validate(&c.user)
+		if c.expected == nil {
+			if err != nil {
+				t.Errorf("case %d: %v", i, err)
+			}
+			continue
+		}
+		e, ok := err.(*validator.ValidationError)
+		if !ok {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		m := e.Map()
+		for path, message := range c.expected {
+			if m[path] != message {
+				t.Errorf("case %d: %s: expected '%s', got '%s'", i, path, message, m[path])
+			}
+		}
+		if len(m) != len(c.expected) {
+			t.Errorf("case %d: unexpected errors: %v", i, m)
+		}
+	}
+}
+
+func TestLoadRulesError(t *testing.T) {
+	cases := []struct {
+		doc    string
+		target error
+	}{
+		{`{"RulesUser": [1]}`, validator.ErrInvalidRules},
+		{"RulesUser:\n  Name: 'required\n", validator.ErrInvalidRules},
+		{"  Name: required\n", validator.ErrInvalidRules},
+		{"RulesUser:\n  Name: !eq:x\n", validator.ErrInvalidRules},
+		{"RulesUser:\n  Name: [required]\n", validator.ErrInvalidRules},
+		{"RulesUser:\n  Name: |\n    required\n", validator.ErrInvalidRules},
+		{"RulesUser:\n  Address:\n    City: required\n", validator.ErrInvalidRules},
+		{"- RulesUser:\n  Name: required\n", validator.ErrInvalidRules},
+		{`{"Unknown": {"Name": "required"}}`, validator.ErrInvalidRules},
+		{`{"RulesUser": {"Phone": "required"}}`, validator.ErrInvalidRules},
+		{`{"RulesUser": {"Name.First": "required"}}`, validator.ErrInvalidRules},
+		{`{"RulesUser": {"Name": "required,!dive"}}`, validator.ErrInvalidTag},
+		{`{"RulesUser": {"Name": "uuid"}}`, validator.ErrUnregisteredValidator},
+		{`{"RulesUser": {"Age": "len:x"}}`, validator.ErrInvalidParam},
+	}
+	for i, c := range cases {
+		v := validator.New()
+		v.MustRegisterType(&RulesUser{})
+		if err := v.LoadRules(strings.NewReader(c.doc)); !errors.Is(err, c.target) {
+			t.Errorf("case %d: expected %v, got %v", i, c.target, err)
+		}
+		// nothing is loaded on error
+		if err := v.Validate(&RulesUser{Name: "Tom", Email: "tom@example.com", Address: RulesAddress{City: "Paris"}}); err != nil {
+			t.Errorf("case %d: %v", i, err)
+		}
+	}
+}
+
+func TestLoadRulesConcurrent(t *testing.T) {
+	v := validator.New()
+	v.MustRegisterType(&RulesUser{})
+	docs := []string{
+		`{"RulesUser": {"Age": "gte:18"}}`,
+		`{"RulesUser": {"Tags": "len:1-3"}}`,
+		`{"RulesUser": {"Address.Zip": "required"}}`,
+		`{"RulesUser": {"Name": "len:x"}}`,
+		`{"RulesUser": {"Email": "uuid"}}`,
+	}
+	user := RulesUser{Name: "Tom", Email: "tom@example.com", Address: RulesAddress{City: "Paris"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		doc := docs[i%len(docs)]
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			v.LoadRules(strings.NewReader(doc))
+		}()
+		go func() {
+			defer wg.Done()
+			// the invalid rules are never used
+			if err := v.Validate(&user); err != nil {
+				if _, ok := err.(*validator.ValidationError); !ok {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	e, ok := v.Validate(&user).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	m := e.Map()
+	for _, path := range []string{"Age", "Tags", "Address.Zip"} {
+		if m[path] == "" {
+			t.Errorf("the rules of %s are lost: %v", path, m)
+		}
+	}
+	if len(m) != 3 {
+		t.Errorf("unexpected errors: %v", m)
+	}
+}
+
+func TestLoadRulesMergeOr(t *testing.T) {
+	v := validator.New()
+	v.MustRegisterType(&RulesContact{})
+	if err := v.LoadRules(strings.NewReader(`{"RulesContact": {"Contact": "+lower|upper"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		contact  string
+		expected []string
+	}{
+		{"tom@example.com", nil},
+		{"Tom@example.com", []string{"lower", "upper"}},
+		{"tom", []string{"phone", "email"}},
+	}
+	for _, c := range cases {
+		err := v.Validate(&RulesContact{Contact: c.contact})
+		if c.expected == nil {
+			if err != nil {
+				t.Errorf("%s: %v", c.contact, err)
+			}
+			continue
+		}
+		e, ok := err.(*validator.ValidationError)
+		if !ok {
+			t.Fatalf("%s: %v", c.contact, err)
+		}
+		feedbacks := e.Detail[0].Feedbacks
+		if len(e.Detail) != 1 || len(feedbacks) != 1 || feedbacks[0].Code != "or" {
+			t.Fatalf("%s: unexpected errors: %v", c.contact, e.Map())
+		}
+		if alternatives := feedbacks[0].Params["alternatives"].([]string); strings.Join(alternatives, "|") != strings.Join(c.expected, "|") {
+			t.Errorf("%s: unexpected alternatives: %v", c.contact, alternatives)
+		}
+	}
+}
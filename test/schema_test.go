@@ -0,0 +1,106 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/schema"
+	"reflect"
+	"testing"
+)
+
+type schemaAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip,omitempty" validate:"blank,len:6,number"`
+}
+
+type SchemaBase struct {
+	ID int64 `json:"id" validate:"gt:0"`
+}
+
+type schemaUser struct {
+	SchemaBase
+	Name     string         `json:"name" validate:"required,len:2-20"`
+	Email    string         `json:"email" validate:"blank,email"`
+	Phone    *string        `json:"phone" validate:"required_without:Email"`
+	Age      uint8          `json:"age" validate:"gte:18,lte:130"`
+	Role     string         `json:"role" validate:"required"`
+	Admin    string         `json:"admin" validate:"required_if:Role admin"`
+	Website  string         `json:"website" validate:"prefix:https://|prefix:http://"`
+	Tags     []string       `json:"tags" validate:"len:1-5,dive,lower"`
+	Limits   map[string]int `json:"limits" validate:"dive,keys,len:2-8,endkeys,gte:0"`
+	Address  schemaAddress  `json:"address"`
+	Shipping *schemaAddress `json:"shipping"`
+	Password string         `json:"-" validate:"password"`
+	Code     string         `validate:"uuid"`
+}
+
+func TestSchema(t *testing.T) {
+	s, err := schema.Generate(&schemaUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$defs":{"schemaAddress":{"properties":{"city":{"minLength":1,"type":"string"},` +
+		`"zip":{"anyOf":[{"const":""},{"maxLength":6,"minLength":6,"pattern":"^\\d+$"}],"type":"string"}},"required":["city"],"type":"object"}},` +
+		`"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+		`"allOf":[{"if":{"not":{"required":["email"]}},"then":{"required":["phone"]}},` +
+		`{"if":{"properties":{"role":{"const":"admin"}},"required":["role"]},"then":{"required":["admin"]}}],` +
+		`"properties":{"Code":{"type":"string"},"address":{"$ref":"#/$defs/schemaAddress"},"admin":{"type":"string"},` +
+		`"age":{"maximum":130,"minimum":18,"type":"integer"},"email":{"anyOf":[{"const":""},{"format":"email"}],"type":"string"},` +
+		`"id":{"exclusiveMinimum":0,"type":"integer"},` +
+		`"limits":{"additionalProperties":{"minimum":0,"type":"integer"},"propertyNames":{"maxLength":8,"minLength":2},"type":"object"},` +
+		`"name":{"maxLength":20,"minLength":2,"type":"string"},"phone":{"type":["string","null"]},"role":{"minLength":1,"type":"string"},` +
+		`"shipping":{"anyOf":[{"$ref":"#/$defs/schemaAddress"},{"type":"null"}]},` +
+		`"tags":{"items":{"pattern":"^[^A-Z]*$","type":"string"},"maxItems":5,"minItems":1,"type":"array"},` +
+		`"website":{"anyOf":[{"pattern":"^https://"},{"pattern":"^http://"}],"type":"string"}},` +
+		`"required":["name","role"],"title":"schemaUser","type":"object"}`
+	if string(data) != expected {
+		t.Errorf("unexpected schema: %s", data)
+	}
+}
+
+type schemaNode struct {
+	Name     string       `json:"name" validate:"!upper"`
+	Children []schemaNode `json:"children" validate:"dive"`
+}
+
+func TestSchemaRecursive(t *testing.T) {
+	s, err := schema.Generate(reflect.TypeOf(schemaNode{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(s)
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"children":{"items":{"$ref":"#"},"type":"array"},` +
+		`"name":{"not":{"pattern":"^[^a-z]*$"},"type":"string"}},"title":"schemaNode","type":"object"}`
+	if string(data) != expected {
+		t.Errorf("unexpected schema: %s", data)
+	}
+}
+
+func TestSchemaFunc(t *testing.T) {
+	g := schema.New()
+	g.RegisterFunc("uuid", func(s schema.Schema, flag validator.Flag, typ reflect.Type) error {
+		s["format"] = "uuid"
+		return nil
+	})
+	s, err := g.Generate(schemaUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := s["properties"].(schema.Schema)["Code"].(schema.Schema)
+	if code["format"] != "uuid" {
+		t.Errorf("unexpected schema: %v", code)
+	}
+
+	if _, err := g.Generate(struct {
+		Age int `validate:"gt:a"`
+	}{}); err == nil {
+		t.Error("expected error for invalid param")
+	}
+	if _, err := g.Generate(1); err == nil {
+		t.Error("expected error for non-struct type")
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"testing"
+	"time"
+)
+
+type contactForm struct {
+	Name  string `validate:"required"`
+	Phone string
+	Email string `validate:"blank,email"`
+}
+
+func (self *contactForm) ValidateWith(sl *validator.StructLevel) {
+	if self.Phone == "" && self.Email == "" {
+		sl.ReportError("Phone", "required_without", "Email", "phone or email is required")
+		sl.ReportError("Email", "required_without", "Phone", "phone or email is required")
+	}
+}
+
+type bookingForm struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Contact   contactForm
+}
+
+func (self bookingForm) ValidateStruct() error {
+	if self.EndDate.Sub(self.StartDate) > 30*24*time.Hour {
+		return errors.New("booking must not exceed 30 days")
+	}
+	return nil
+}
+
+type periodForm struct {
+	Days int
+}
+
+// ValidateStruct returns a field error without path, as Engine.Var does
+func (self periodForm) ValidateStruct() error {
+	return validator.New().Var(self.Days, "gt:0")
+}
+
+type tripForm struct {
+	Period periodForm
+}
+
+type thirdPartyForm struct {
+	Min int
+	Max int `validate:"gt:0"`
+}
+
+func TestStructLevel(t *testing.T) {
+	v := validator.New()
+	v.RegisterStructValidator(thirdPartyForm{}, func(sl *validator.StructLevel) {
+		form := sl.Struct.Interface().(thirdPartyForm)
+		if form.Min > form.Max {
+			sl.ReportError("Max", "gte_field", "Min", "max must be greater than or equal to min")
+		}
+	})
+	start := time.Now()
+	form := bookingForm{
+		StartDate: start,
+		EndDate:   start.Add(40 * 24 * time.Hour),
+		Contact:   contactForm{Name: "jack"},
+	}
+	e, ok := v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	m := e.Map()
+	expected := map[string]string{
+		"Contact.Phone": "phone or email is required",
+		"Contact.Email": "phone or email is required",
+		"":              "booking must not exceed 30 days",
+	}
+	if len(m) != len(expected) {
+		t.Errorf("unexpected errors: %v", m)
+	}
+	for k, s := range expected {
+		if m[k] != s {
+			t.Errorf("unexpected error of '%s': %v", k, m)
+		}
+	}
+
+	e, ok = v.Validate(&thirdPartyForm{Min: 10, Max: -1}).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if len(e.Detail) != 1 || len(e.Detail[0].Feedbacks) != 2 {
+		t.Errorf("expected merged feedbacks: %v", e)
+	}
+}
+
+func TestStructLevelNested(t *testing.T) {
+	e, ok := validator.New().Validate(&tripForm{}).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	if len(e.Detail) != 1 || e.Detail[0].Path != "Period" {
+		t.Errorf("expected the error of the nested struct at 'Period': %v", e.Map())
+	}
+}
+
+var signupEngine = validator.New()
+
+type signupForm struct {
+	Name string `validate:"required"`
+}
+
+// Validate is the common method calling the engine on its receiver, it isn't a struct level hook
+func (self *signupForm) Validate() error {
+	return signupEngine.Validate(self)
+}
+
+func TestStructLevelValidateMethod(t *testing.T) {
+	e, ok := (&signupForm{}).Validate().(*validator.ValidationError)
+	if !ok || len(e.Detail) != 1 || e.Detail[0].Path != "Name" {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
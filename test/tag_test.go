@@ -0,0 +1,89 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"testing"
+)
+
+type tagForm struct {
+	Contact string `validate:"required,ip:v4|email"`
+	Table   string `validate:"!prefix:tmp_,not:suffix:_bak"`
+	Quoted  string `validate:"prefix:'a,b|c:d'"`
+	Escaped string `validate:"suffix:x\\,y"`
+	Spaces  string `validate:"eq:' a '"`
+}
+
+func TestOrAndNot(t *testing.T) {
+	v := validator.New()
+	form := &tagForm{
+		Contact: "127.0.0.1",
+		Table:   "users",
+		Quoted:  "a,b|c:d!",
+		Escaped: "x,y",
+		Spaces:  " a ",
+	}
+	if err := v.Validate(form); err != nil {
+		t.Fatal(err)
+	}
+	form.Contact = "jack@gmail.com"
+	if err := v.Validate(form); err != nil {
+		t.Fatal(err)
+	}
+
+	form = &tagForm{
+		Contact: "abc",
+		Table:   "tmp_users_bak",
+		Quoted:  "a",
+		Escaped: "x",
+		Spaces:  "a",
+	}
+	e, ok := v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	m := e.Map()
+	expected := map[string]string{
+		"Contact": "invalid ipv4 address or invalid email format",
+		"Table":   "field must not match 'prefix:tmp_';field must not match 'suffix:_bak'",
+		"Quoted":  "field must contain the string prefix 'a,b|c:d'",
+		"Escaped": "field must contain the string suffix 'x,y'",
+		"Spaces":  "field value must be equal  a ",
+	}
+	for k, s := range expected {
+		if m[k] != s {
+			t.Errorf("unexpected error of '%s': %s", k, m[k])
+		}
+	}
+	feedback := e.Detail[0].Feedbacks[0]
+	if feedback.Validation.Flag != "or" || len(feedback.Alternatives()) != 2 {
+		t.Errorf("unexpected or feedback: %v", feedback)
+	}
+	e.SetTranslation(hans.New())
+	if s := e.Map()["Contact"]; s != "无效的ipv4地址或无效的电子邮箱地址" {
+		t.Errorf("unexpected translation: %s", s)
+	}
+}
+
+type badTagForm struct {
+	A string `validate:"prefix:'abc"`
+}
+
+type badGroupForm struct {
+	A string `validate:"blank|required"`
+}
+
+type badNegateForm struct {
+	A []string `validate:"!dive,required"`
+}
+
+func TestBadTag(t *testing.T) {
+	v := validator.New()
+	for _, form := range []interface{}{&badTagForm{}, &badGroupForm{}, &badNegateForm{}} {
+		if err := v.Validate(form); err == nil {
+			t.Errorf("expected tag error for %T", form)
+		} else if _, ok := err.(*validator.ValidationError); ok {
+			t.Errorf("expected tag error for %T, got %v", form, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"testing"
+)
+
+type tagNameAddress struct {
+	Zip string `json:"zip_code" validate:"len:6"`
+}
+
+type TagNameBase struct {
+	ID int `json:"id" validate:"gt:0"`
+}
+
+type tagNameForm struct {
+	TagNameBase
+	UserName  string            `json:"username,omitempty" validate:"required"`
+	Password  string            `json:"password" validate:"required"`
+	Password2 string            `json:"password2" validate:"eq_field:Password"`
+	Secret    string            `json:"-" validate:"required"`
+	Plain     string            `validate:"required"`
+	Address   tagNameAddress    `json:"address"`
+	Emails    []string          `json:"emails" validate:"dive,email"`
+	Limits    map[string]int    `json:"limits" validate:"dive,gte:0"`
+	Extra     map[string]string `json:"extra" validate:"required_with:Emails"`
+}
+
+func TestTagNameFunc(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(validator.TagName("json"))
+	form := &tagNameForm{
+		Password:  "abc",
+		Password2: "abd",
+		Address:   tagNameAddress{Zip: "1"},
+		Emails:    []string{"x"},
+		Limits:    map[string]int{"cpu": -1},
+	}
+	e, ok := v.Validate(form).(*validator.ValidationError)
+	if !ok {
+		t.Fatal("expected validation error")
+	}
+	m := e.Map()
+	expected := map[string]string{
+		"id":               "field value must be greater than 0",
+		"username":         "field is required",
+		"password2":        "field must be equal to field 'password'",
+		"Secret":           "field is required",
+		"Plain":            "field is required",
+		"address.zip_code": "field length must be 6 characters",
+		"emails[0]":        "invalid email format",
+		"limits[cpu]":      "field value must be greater than or equal to 0",
+		"extra":            "field is required when emails is present",
+	}
+	if len(m) != len(expected) {
+		t.Errorf("unexpected errors: %v", m)
+	}
+	for k, s := range expected {
+		if m[k] != s {
+			t.Errorf("unexpected error of '%s': %s", k, m[k])
+		}
+	}
+	if e.Detail[0].Name != "id" {
+		t.Errorf("unexpected name: %s", e.Detail[0].Name)
+	}
+	e.SetTranslation(hans.New())
+	if s := e.Map()["password2"]; s != "该字段必须等于'password'字段" {
+		t.Errorf("unexpected translation: %s", s)
+	}
+}
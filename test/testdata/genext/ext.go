@@ -0,0 +1,9 @@
+// Package genext nests a struct of another package, which validatorgen can't generate
+package genext
+
+import "github.com/shaopson/validator/test/gen"
+
+type User struct {
+	Name string `validate:"required"`
+	Addr gen.Address
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"errors"
+	"github.com/shaopson/validator"
+	"testing"
+)
+
+type TypedBase struct {
+	ID int
+}
+
+type TypedUser struct {
+	TypedBase
+	Name     string
+	Password string
+	Confirm  string
+	Address  BuilderAddress
+	Profile  *BuilderAddress
+	secret   string
+}
+
+func TestFieldPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{validator.FieldPath(func(u *TypedUser) *string { return &u.Name }), "Name"},
+		{validator.FieldPath(func(u *TypedUser) *int { return &u.ID }), "ID"},
+		{validator.FieldPath(func(u *TypedUser) *TypedBase { return &u.TypedBase }), "TypedBase"},
+		{validator.FieldPath(func(u *TypedUser) **BuilderAddress { return &u.Profile }), "Profile"},
+	}
+	for _, c := range cases {
+		if c.path != c.expected {
+			t.Errorf("expected '%s', got '%s'", c.expected, c.path)
+		}
+	}
+	selectors := []func(u *TypedUser) *string{
+		func(u *TypedUser) *string { return &u.secret },
+		// the params don't name the fields of nested structs
+		func(u *TypedUser) *string { return &u.Address.Zip },
+	}
+	for i, sel := range selectors {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, validator.ErrInvalidRules) {
+					t.Errorf("case %d: expected panic with %v, got %v", i, validator.ErrInvalidRules, err)
+				}
+			}()
+			validator.FieldPath(sel)
+		}()
+	}
+}
+
+func TestSelect(t *testing.T) {
+	v := validator.New()
+	err := v.RegisterRules(validator.Rules[TypedUser]().
+		Select(validator.Sel(func(u *TypedUser) *int { return &u.ID }), validator.Gt(0)).
+		Select(validator.Sel(func(u *TypedUser) *string { return &u.Name }), validator.Required()).
+		Select(validator.Sel(func(u *TypedUser) *string { return &u.Confirm }),
+			validator.EqField(validator.FieldPath(func(u *TypedUser) *string { return &u.Password }))).
+		Select(validator.Sel(func(u *TypedUser) *string { return &u.Address.City }), validator.Required()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := TypedUser{Password: "a", Confirm: "b"}
+	expected := map[string]string{
+		"ID":           "field value must be greater than 0",
+		"Name":         "field is required",
+		"Confirm":      "field must be equal to field 'Password'",
+		"Address.City": "field is required",
+	}
+	for _, err := range []error{v.Validate(user), v.Validate(&user)} {
+		e, ok := err.(*validator.ValidationError)
+		if !ok {
+			t.Fatal(err)
+		}
+		m := e.Map()
+		for path, message := range expected {
+			if m[path] != message {
+				t.Errorf("%s: expected '%s', got '%s'", path, message, m[path])
+			}
+		}
+		if len(m) != len(expected) {
+			t.Errorf("unexpected errors: %v", m)
+		}
+	}
+}
+
+func TestSelectError(t *testing.T) {
+	selectors := []validator.Selector[TypedUser]{
+		validator.Sel(func(u *TypedUser) *string { return &u.Profile.City }),
+		validator.Sel(func(u *TypedUser) *string { return nil }),
+		validator.Sel(func(u *TypedUser) *TypedUser { return u }),
+		validator.Sel(func(u *TypedUser) *string { return new(string) }),
+		validator.Sel(func(u *TypedUser) *string { return &u.secret }),
+	}
+	for i, sel := range selectors {
+		err := validator.New().RegisterRules(validator.Rules[TypedUser]().Select(sel, validator.Required()))
+		if !errors.Is(err, validator.ErrInvalidRules) {
+			t.Errorf("case %d: expected %v, got %v", i, validator.ErrInvalidRules, err)
+		}
+	}
+}
@@ -55,3 +55,49 @@ func TestBlank(t *testing.T) {
 	}
 
 }
+
+type suffixForm struct {
+	File string `validate:"suffix:.go"`
+}
+
+func TestSuffix(t *testing.T) {
+	v := validator.New()
+	if err := v.Validate(&suffixForm{File: "main.go"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.Validate(&suffixForm{File: ".go.mod"}); err == nil {
+		t.Error("expected an error for a string starting with the suffix")
+	}
+}
+
+type gteFieldForm struct {
+	Min int
+	Max int `validate:"gte_field:Min"`
+}
+
+func TestGteFieldFeedback(t *testing.T) {
+	err := validator.New().Validate(&gteFieldForm{Min: 2, Max: 1})
+	e, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m := e.Map(); m["Max"] != "field must be greater than or equal to field 'Min'" {
+		t.Errorf("unexpected feedback: %v", m)
+	}
+}
+
+type passwordPointerForm struct {
+	Password *string `validate:"password:1"`
+}
+
+func TestPasswordPointer(t *testing.T) {
+	v := validator.New()
+	good, bad := "abc123", "abcdef"
+	if err := v.Validate(&passwordPointerForm{Password: &good}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	e, ok := v.Validate(&passwordPointerForm{Password: &bad}).(*validator.ValidationError)
+	if !ok || e.Map()["Password"] != "password must contain letters and numbers" {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
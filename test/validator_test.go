@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/shaopson/validator"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -158,3 +159,32 @@ func TestEq(t *testing.T) {
 		}
 	}
 }
+
+type orderForm struct {
+	Email string `validate:"required,email,len:5-10"`
+	Name  string `validate:"len:2-4,lower,prefix:a"`
+}
+
+func TestFlagOrder(t *testing.T) {
+	form := &orderForm{Name: "XYZXYZ"}
+	v := validator.New()
+	expected := "Email: field is required;invalid email format;field length must be 5-10 characters\n" +
+		"Name: field length must be 2-4 characters;field must must be a lowercase string;field must contain the string prefix 'a'"
+	for i := 0; i < 20; i++ {
+		err := v.Validate(form)
+		e, ok := err.(*validator.ValidationError)
+		if !ok {
+			t.Fatal(err)
+		}
+		if e.Error() != expected {
+			t.Fatalf("unexpected error: %s", e.Error())
+		}
+		flags := make([]string, 0)
+		for _, f := range e.Detail[0].Feedbacks {
+			flags = append(flags, f.Validation.Flag)
+		}
+		if strings.Join(flags, ",") != "required,email,len" {
+			t.Fatalf("unexpected order: %v", flags)
+		}
+	}
+}
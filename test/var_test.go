@@ -0,0 +1,62 @@
+package test
+
+import (
+	"github.com/shaopson/validator"
+	"github.com/shaopson/validator/feedback/hans"
+	"testing"
+)
+
+func TestVar(t *testing.T) {
+	v := validator.New()
+	if err := v.Var("jack@gmail.com", "required,email"); err != nil {
+		t.Error(err)
+	}
+	err := v.Var("", "required,email")
+	e, ok := err.(*validator.FieldError)
+	if !ok {
+		t.Fatalf("expected *FieldError, got %v", err)
+	}
+	if len(e.Feedbacks) != 2 || e.Error() != "field is required;invalid email format" {
+		t.Errorf("unexpected error: %v", e)
+	}
+	if e.Translate(hans.New()) != "该字段是必填的;无效的电子邮箱地址" {
+		t.Errorf("unexpected translation: %s", e.Translate(hans.New()))
+	}
+	s := "abc"
+	if err := v.Var(&s, "len:3"); err != nil {
+		t.Error(err)
+	}
+	if _, ok := v.Var(nil, "required").(*validator.FieldError); !ok {
+		t.Error("expected *FieldError for nil value")
+	}
+	if err := v.Var(10, "unknown"); err == nil {
+		t.Error("expected unregistered validator error")
+	}
+
+	err = v.Var([]string{"a@b.com", "x"}, "dive,email")
+	ve, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if _, ok := ve.Map()["[1]"]; !ok || len(ve.Detail) != 1 {
+		t.Errorf("unexpected error: %v", ve.Map())
+	}
+}
+
+func TestVarWithField(t *testing.T) {
+	v := validator.New()
+	if err := v.VarWithField("abc", "abc", "eq_field"); err != nil {
+		t.Error(err)
+	}
+	if err := v.VarWithField(10, 5, "gt_field,lte_field:Max"); err == nil {
+		t.Error("expected lte_field error")
+	} else if err.Error() != "field must be less than or equal to field 'Max'" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.VarWithField("a", "b", "eq_field"); err == nil || err.Error() != "field must be equal to field 'Other'" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, ok := v.Var(10, "gt_field:Min").(*validator.FieldError); ok {
+		t.Error("expected param error without other value")
+	}
+}
@@ -0,0 +1,116 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Selector is a field of T selected by a typed accessor, see Sel
+type Selector[T any] struct {
+	path string
+	err  error
+}
+
+// Sel selects the field of T whose address sel returns, for TypeRules.Select:
+//
+//	validator.Sel(func(u *User) *string { return &u.Email })
+//
+// sel is called with a zero T, it must return the address of a field without going through pointers
+func Sel[T any, F any](sel func(*T) *F) Selector[T] {
+	path, err := selectPath(reflect.TypeOf((*T)(nil)).Elem(), func(x interface{}) interface{} {
+		return sel(x.(*T))
+	})
+	return Selector[T]{path: path, err: err}
+}
+
+// FieldPath returns the name of the field of T returned by sel, for the params of the *_field and
+// conditional validators:
+//
+//	validator.EqField(validator.FieldPath(func(u *User) *string { return &u.Password }))
+//
+// Renaming the field breaks the build instead of the rules. The field must be a field of T or promoted
+// from an embedded struct, the params don't name the fields of nested structs. sel is called as by Sel,
+// FieldPath panics if it doesn't return the address of such a field
+func FieldPath[T any, F any](sel func(*T) *F) string {
+	field := Sel(sel)
+	if field.err != nil {
+		panic(field.err)
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	name := field.path[strings.LastIndex(field.path, ".")+1:]
+	if promoted, ok := typ.FieldByName(name); !ok || !reflect.DeepEqual(promoted.Index, pathIndex(typ, field.path)) {
+		panic(&ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("FieldPath '%s': '%s' is not a field of the struct, select it with Sel", typ, field.path)})
+	}
+	return name
+}
+
+// pathIndex returns the index of the field at the dotted path, see reflect.StructField.Index
+func pathIndex(typ reflect.Type, path string) []int {
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		field, _ := typ.FieldByName(name)
+		index = append(index, field.Index...)
+		typ = field.Type
+	}
+	return index
+}
+
+// Select sets the rules of the field selected by Sel, the selector must be of T:
+//
+//	validator.Rules[User]().Select(validator.Sel(func(u *User) *string { return &u.Email }), validator.Email())
+func (self *TypeRules[T]) Select(field Selector[T], flags ...Flag) *TypeRules[T] {
+	if field.err != nil {
+		self.errs = append(self.errs, field.err)
+		return self
+	}
+	return self.Field(field.path, flags...)
+}
+
+// selectPath finds the field of the struct typ by the address sel returns for a new value of typ
+func selectPath(typ reflect.Type, sel func(interface{}) interface{}) (path string, err error) {
+	if typ.Kind() != reflect.Struct {
+		return "", &ConfigError{Err: ErrInvalidValue, s: fmt.Sprintf("Only support select fields of 'Struct' type, got '%v'", typ)}
+	}
+	base := reflect.New(typ)
+	defer func() {
+		if r := recover(); r != nil {
+			err = &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Select '%s': %v", typ, r)}
+		}
+	}()
+	ptr := reflect.ValueOf(sel(base.Interface()))
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return "", &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Select '%s': the selector must return the address of a field", typ)}
+	}
+	offset := ptr.Pointer() - base.Pointer()
+	if ptr.Pointer() < base.Pointer() || offset >= typ.Size() {
+		return "", &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Select '%s': the selector must return the address of a field", typ)}
+	}
+	if path, ok := fieldAt(typ, offset, ptr.Type().Elem()); ok {
+		return path, nil
+	}
+	return "", &ConfigError{Err: ErrInvalidRules, s: fmt.Sprintf("Select '%s': no exported field of type '%s' at the address", typ, ptr.Type().Elem())}
+}
+
+// fieldAt returns the path of the exported field of typ at offset, nested structs are searched as well
+func fieldAt(typ reflect.Type, offset uintptr, fieldTyp reflect.Type) (string, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if offset < field.Offset || offset >= field.Offset+field.Type.Size() {
+			continue
+		}
+		if field.Offset == offset && field.Type == fieldTyp && field.IsExported() {
+			return field.Name, true
+		}
+		if field.Type.Kind() == reflect.Struct && (field.IsExported() || field.Anonymous) {
+			if path, ok := fieldAt(field.Type, offset-field.Offset, fieldTyp); ok {
+				if field.Anonymous && !field.IsExported() {
+					// the fields of an unexported embedded struct are selected by their promoted name
+					return path, true
+				}
+				return field.Name + "." + path, true
+			}
+		}
+	}
+	return "", false
+}
@@ -5,7 +5,6 @@ import (
 	"net"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -36,6 +35,15 @@ var defaultValidators = map[string]Validator{
 	"gte_field": gtefieldValidator,
 	"prefix":    prefixValidator,
 	"suffix":    suffixValidator,
+
+	"required_if":      requiredIfValidator,
+	"required_unless":  requiredUnlessValidator,
+	"required_with":    requiredWithValidator,
+	"required_without": requiredWithoutValidator,
+	"excluded_if":      excludedIfValidator,
+	"excluded_unless":  excludedUnlessValidator,
+	"excluded_with":    excludedWithValidator,
+	"excluded_without": excludedWithoutValidator,
 }
 
 var timeType = reflect.TypeOf(time.Time{})
@@ -48,23 +56,15 @@ func requiredValidator(v *Validation) error {
 }
 
 func lenValidator(v *Validation) error {
-	if v.Param == "" {
-		return v.ValidatorError("missing param")
-	}
-	params := strings.SplitN(v.Param, "-", 2)
-	args := make([]int, len(params))
-	for i, param := range params {
-		if arg, err := strconv.Atoi(param); err != nil {
-			return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
-		} else {
-			args[i] = arg
-		}
+	args, err := v.lenParam()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
 	s := fmt.Sprintf("field length must be %s characters", v.Param)
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return lenError(v, args, s)
 		}
 		field = field.Elem()
 	}
@@ -78,9 +78,9 @@ func lenValidator(v *Validation) error {
 			return nil
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return lenError(v, args, s)
 }
 
 // equal
@@ -89,7 +89,7 @@ func eqValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 		}
 		field = field.Elem()
 	}
@@ -99,55 +99,46 @@ func eqValidator(v *Validation) error {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if param, err := strconv.ParseInt(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.intParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Int() == param {
 			return nil
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if param, err := strconv.ParseUint(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.uintParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Uint() == param {
 			return nil
 		}
 	case reflect.Float32:
-		if param, err := strconv.ParseFloat(v.Param, 32); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(32); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() == param {
 			return nil
 		}
 	case reflect.Float64:
-		if param, err := strconv.ParseFloat(v.Param, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(64); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() == param {
 			return nil
 		}
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
-			var t time.Time
-			var err error
-			if strings.Contains(v.Param, ":") {
-				if t, err = time.Parse("2006-01-02 15:04:05", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else if strings.Contains(v.Param, "-") { //2006-01-02
-				if t, err = time.Parse("2006-01-02", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else {
-				return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+			t, err := v.timeParam()
+			if err != nil {
+				return v.InvalidParam(err.Error())
 			}
 			value := field.Interface().(time.Time)
 			if value.Equal(t) {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 }
 
 // greater
@@ -156,7 +147,7 @@ func gtValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 		}
 		field = field.Elem()
 	}
@@ -166,55 +157,46 @@ func gtValidator(v *Validation) error {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if param, err := strconv.ParseInt(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.intParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Int() > param {
 			return nil
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if param, err := strconv.ParseUint(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.uintParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Uint() > param {
 			return nil
 		}
 	case reflect.Float32:
-		if param, err := strconv.ParseFloat(v.Param, 32); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(32); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() > param {
 			return nil
 		}
 	case reflect.Float64:
-		if param, err := strconv.ParseFloat(v.Param, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(64); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() > param {
 			return nil
 		}
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
-			var t time.Time
-			var err error
-			if strings.Contains(v.Param, ":") {
-				if t, err = time.Parse("2006-01-02 15:04:05", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else if strings.Contains(v.Param, "-") { //2006-01-02
-				if t, err = time.Parse("2006-01-02", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else {
-				return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+			t, err := v.timeParam()
+			if err != nil {
+				return v.InvalidParam(err.Error())
 			}
 			value := field.Interface().(time.Time)
 			if value.After(t) {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 }
 
 // greater than or equal
@@ -223,7 +205,7 @@ func gteValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 		}
 		field = field.Elem()
 	}
@@ -233,55 +215,46 @@ func gteValidator(v *Validation) error {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if param, err := strconv.ParseInt(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.intParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Int() >= param {
 			return nil
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if param, err := strconv.ParseUint(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.uintParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Uint() >= param {
 			return nil
 		}
 	case reflect.Float32:
-		if param, err := strconv.ParseFloat(v.Param, 32); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(32); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() >= param {
 			return nil
 		}
 	case reflect.Float64:
-		if param, err := strconv.ParseFloat(v.Param, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(64); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() >= param {
 			return nil
 		}
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
-			var t time.Time
-			var err error
-			if strings.Contains(v.Param, ":") {
-				if t, err = time.Parse("2006-01-02 15:04:05", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else if strings.Contains(v.Param, "-") { //2006-01-02
-				if t, err = time.Parse("2006-01-02", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else {
-				return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+			t, err := v.timeParam()
+			if err != nil {
+				return v.InvalidParam(err.Error())
 			}
 			value := field.Interface().(time.Time)
 			if value.After(t) || value.Equal(t) {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 }
 
 // less than
@@ -290,7 +263,7 @@ func ltValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 		}
 		field = field.Elem()
 	}
@@ -300,55 +273,46 @@ func ltValidator(v *Validation) error {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if param, err := strconv.ParseInt(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.intParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Int() < param {
 			return nil
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if param, err := strconv.ParseUint(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.uintParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Uint() < param {
 			return nil
 		}
 	case reflect.Float32:
-		if param, err := strconv.ParseFloat(v.Param, 32); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(32); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() < param {
 			return nil
 		}
 	case reflect.Float64:
-		if param, err := strconv.ParseFloat(v.Param, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(64); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() < param {
 			return nil
 		}
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
-			var t time.Time
-			var err error
-			if strings.Contains(v.Param, ":") {
-				if t, err = time.Parse("2006-01-02 15:04:05", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else if strings.Contains(v.Param, "-") { //2006-01-02
-				if t, err = time.Parse("2006-01-02", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else {
-				return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+			t, err := v.timeParam()
+			if err != nil {
+				return v.InvalidParam(err.Error())
 			}
 			value := field.Interface().(time.Time)
 			if value.Before(t) {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 }
 
 // less than or equal
@@ -357,7 +321,7 @@ func lteValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 		}
 		field = field.Elem()
 	}
@@ -367,55 +331,46 @@ func lteValidator(v *Validation) error {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if param, err := strconv.ParseInt(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.intParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Int() <= param {
 			return nil
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if param, err := strconv.ParseUint(v.Param, 0, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.uintParam(); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Uint() <= param {
 			return nil
 		}
 	case reflect.Float32:
-		if param, err := strconv.ParseFloat(v.Param, 32); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(32); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() <= param {
 			return nil
 		}
 	case reflect.Float64:
-		if param, err := strconv.ParseFloat(v.Param, 64); err != nil {
-			return v.ValidatorError("parse param failure:" + err.Error())
+		if param, err := v.floatParam(64); err != nil {
+			return v.InvalidParam("parse param failure:" + err.Error())
 		} else if field.Float() <= param {
 			return nil
 		}
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
-			var t time.Time
-			var err error
-			if strings.Contains(v.Param, ":") {
-				if t, err = time.Parse("2006-01-02 15:04:05", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else if strings.Contains(v.Param, "-") { //2006-01-02
-				if t, err = time.Parse("2006-01-02", v.Param); err != nil {
-					return v.ValidatorError("parse param failure:" + err.Error())
-				}
-			} else {
-				return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+			t, err := v.timeParam()
+			if err != nil {
+				return v.InvalidParam(err.Error())
 			}
 			value := field.Interface().(time.Time)
 			if value.Before(t) || value.Equal(t) {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
-	return v.Error(s)
+	return v.ErrorCode(v.Flag, map[string]any{"value": v.valueParam()}, s)
 }
 
 var emailRegx = regexp.MustCompile("^[0-9a-zA-Z_-]+@[0-9a-zA-Z_-]+(.[0-9a-zA-Z_-]+)+$")
@@ -430,10 +385,10 @@ func emailValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
 	value := field.String()
-	if ok := emailRegx.MatchString(value); !ok {
+	if !matchEmail(value) {
 		return v.Error(s)
 	}
 	return nil
@@ -453,14 +408,10 @@ func phoneValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
 	value := field.String()
-	regx := phoneRegx
-	if strings.HasPrefix(value, "+86") {
-		regx = chinaPhoneRegx
-	}
-	if ok := regx.MatchString(value); !ok {
+	if !matchPhone(value) {
 		return v.Error(s)
 	}
 	return nil
@@ -468,29 +419,32 @@ func phoneValidator(v *Validation) error {
 
 func ipValidator(v *Validation) (err error) {
 	var s string
+	code := v.Flag
 	switch v.Param {
 	case "":
 		s = "invalid ip address"
 	case "v4":
 		s = "invalid ipv4 address"
+		code += ".v4"
 	case "v6":
 		s = "invalid ipv6 address"
+		code += ".v6"
 	default:
-		return v.ValidatorError(fmt.Sprintf("invalid param '%s'", v.Param))
+		return v.InvalidParam(fmt.Sprintf("invalid param '%s'", v.Param))
 	}
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(s)
+			return v.ErrorCode(code, nil, s)
 		}
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
 	value := field.String()
-	if ip := net.ParseIP(value); ip == nil {
-		return v.Error(s)
+	if !matchIP(value) {
+		return v.ErrorCode(code, nil, s)
 	}
 	return nil
 }
@@ -508,13 +462,13 @@ func numberValidator(v *Validation) error {
 	}
 	switch field.Kind() {
 	case reflect.String:
-		if numberRegx.MatchString(field.String()) {
+		if matchNumber(field.String()) {
 			return nil
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return nil
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support type '%s'", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support type '%s'", v.StructField.Type))
 	}
 	return v.Error(s)
 }
@@ -529,9 +483,9 @@ func lowerValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if field.String() == strings.ToLower(field.String()) {
+	if matchLower(field.String()) {
 		return nil
 	}
 	return v.Error(s)
@@ -547,9 +501,9 @@ func upperValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if field.String() == strings.ToUpper(field.String()) {
+	if matchUpper(field.String()) {
 		return nil
 	}
 	return v.Error(feedback)
@@ -567,9 +521,9 @@ func alphaValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if alphaRegex.MatchString(field.String()) {
+	if matchAlpha(field.String()) {
 		return nil
 	}
 	return v.Error(feedback)
@@ -587,9 +541,9 @@ func usernameValidator(v *Validation) error {
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if usernameRegex.MatchString(field.String()) {
+	if matchUsername(field.String()) {
 		return nil
 	}
 	return v.Error(feedback)
@@ -612,57 +566,61 @@ var containLowerRegx = regexp.MustCompile("[a-z]+")
 var containUpperRegx = regexp.MustCompile("[A-Z]+")
 var containSymbolRegx = regexp.MustCompile("[`~!@#$%^&*()\\-_=+[{\\]};:'\",<.>/?]+")
 
+// passwordRegexps are the regexps a password must match by strength
+var passwordRegexps = map[string][]*regexp.Regexp{
+	"":  {containSymbolRegx, containUpperRegx, containLowerRegx, containAlphaRegx, containNumRegx},
+	"3": {containSymbolRegx, containUpperRegx, containLowerRegx, containAlphaRegx, containNumRegx},
+	"2": {containUpperRegx, containLowerRegx, containAlphaRegx, containNumRegx},
+	"1": {containAlphaRegx, containNumRegx},
+}
+
 func passwordValidator(v *Validation) error {
 	feedback := ""
-	var regexps []*regexp.Regexp
+	level := 3
 	switch v.Param {
 	case "3", "":
 		feedback = "password must contain uppercase and lowercase letters, numbers, symbols"
-		regexps = []*regexp.Regexp{containSymbolRegx, containUpperRegx, containLowerRegx, containAlphaRegx, containNumRegx}
 	case "2":
 		feedback = "password must contain uppercase and lowercase letters, numbers"
-		regexps = []*regexp.Regexp{containUpperRegx, containLowerRegx, containAlphaRegx, containNumRegx}
+		level = 2
 	case "1":
 		feedback = "password must contain letters and numbers"
-		regexps = []*regexp.Regexp{containAlphaRegx, containNumRegx}
+		level = 1
 	default:
-		return v.ValidatorError(fmt.Sprintf("invalid parma '%s'", v.Param))
+		return v.InvalidParam(fmt.Sprintf("invalid parma '%s'", v.Param))
 	}
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return v.ErrorCode("password.strength", map[string]any{"level": level}, feedback)
 		}
 		field = field.Elem()
 	}
-	if v.Field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+	if field.Kind() != reflect.String {
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	value := field.String()
-	for _, regex := range regexps {
-		if !regex.MatchString(value) {
-			return v.Error(feedback)
-		}
+	if !matchPassword(v.Param, field.String()) {
+		return v.ErrorCode("password.strength", map[string]any{"level": level}, feedback)
 	}
 	return nil
 }
 
 func eqfieldValidator(v *Validation) error {
-	if _, ok := v.Struct.Type().FieldByName(v.Param); !ok {
-		return v.ValidatorError(fmt.Sprintf("param error: field '%s' not found", v.Param))
+	target, err := v.targetField()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
-	feedback := fmt.Sprintf("field must be equal to field '%s'", v.Param)
+	feedback := fmt.Sprintf("field must be equal to field '%s'", v.FieldName(v.Param))
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		field = field.Elem()
 	}
-	target := v.Struct.FieldByName(v.Param)
 	if target.Kind() == reflect.Pointer {
 		if target.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		target = target.Elem()
 	}
@@ -686,7 +644,7 @@ func eqfieldValidator(v *Validation) error {
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
 			if !target.CanConvert(timeType) {
-				return v.ValidatorError(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
+				return v.UnsupportedType(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
 			}
 			value := field.Interface().(time.Time)
 			targetVal := target.Interface().(time.Time)
@@ -694,30 +652,30 @@ func eqfieldValidator(v *Validation) error {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 	}
-	return v.Error(feedback)
+	return fieldError(v, feedback)
 }
 
 func ltfieldValidator(v *Validation) error {
-	if _, ok := v.Struct.Type().FieldByName(v.Param); !ok {
-		return v.ValidatorError(fmt.Sprintf("param error: field '%s' not found", v.Param))
+	target, err := v.targetField()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
-	feedback := fmt.Sprintf("field must be less than field '%s'", v.Param)
+	feedback := fmt.Sprintf("field must be less than field '%s'", v.FieldName(v.Param))
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		field = field.Elem()
 	}
-	target := v.Struct.FieldByName(v.Param)
 	if target.Kind() == reflect.Pointer {
 		if target.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		target = target.Elem()
 	}
@@ -741,7 +699,7 @@ func ltfieldValidator(v *Validation) error {
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
 			if !target.CanConvert(timeType) {
-				return v.ValidatorError(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
+				return v.UnsupportedType(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
 			}
 			value := field.Interface().(time.Time)
 			targetVal := target.Interface().(time.Time)
@@ -749,30 +707,30 @@ func ltfieldValidator(v *Validation) error {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 	}
-	return v.Error(feedback)
+	return fieldError(v, feedback)
 }
 
 func ltefieldValidator(v *Validation) error {
-	if _, ok := v.Struct.Type().FieldByName(v.Param); !ok {
-		return v.ValidatorError(fmt.Sprintf("param error: field '%s' not found", v.Param))
+	target, err := v.targetField()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
-	feedback := fmt.Sprintf("field must be less than or equal to field '%s'", v.Param)
+	feedback := fmt.Sprintf("field must be less than or equal to field '%s'", v.FieldName(v.Param))
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		field = field.Elem()
 	}
-	target := v.Struct.FieldByName(v.Param)
 	if target.Kind() == reflect.Pointer {
 		if target.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		target = target.Elem()
 	}
@@ -796,7 +754,7 @@ func ltefieldValidator(v *Validation) error {
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
 			if !target.CanConvert(timeType) {
-				return v.ValidatorError(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
+				return v.UnsupportedType(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
 			}
 			value := field.Interface().(time.Time)
 			targetVal := target.Interface().(time.Time)
@@ -804,30 +762,30 @@ func ltefieldValidator(v *Validation) error {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 	}
-	return v.Error(feedback)
+	return fieldError(v, feedback)
 }
 
 func gtfieldValidator(v *Validation) error {
-	if _, ok := v.Struct.Type().FieldByName(v.Param); !ok {
-		return v.ValidatorError(fmt.Sprintf("param error: field '%s' not found", v.Param))
+	target, err := v.targetField()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
-	feedback := fmt.Sprintf("field must be greater than field '%s'", v.Param)
+	feedback := fmt.Sprintf("field must be greater than field '%s'", v.FieldName(v.Param))
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		field = field.Elem()
 	}
-	target := v.Struct.FieldByName(v.Param)
 	if target.Kind() == reflect.Pointer {
 		if target.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		target = target.Elem()
 	}
@@ -851,7 +809,7 @@ func gtfieldValidator(v *Validation) error {
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
 			if !target.CanConvert(timeType) {
-				return v.ValidatorError(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
+				return v.UnsupportedType(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
 			}
 			value := field.Interface().(time.Time)
 			targetVal := target.Interface().(time.Time)
@@ -859,30 +817,30 @@ func gtfieldValidator(v *Validation) error {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 	}
-	return v.Error(feedback)
+	return fieldError(v, feedback)
 }
 
 func gtefieldValidator(v *Validation) error {
-	if _, ok := v.Struct.Type().FieldByName(v.Param); !ok {
-		return v.ValidatorError(fmt.Sprintf("param error: field '%s' not found", v.Param))
+	target, err := v.targetField()
+	if err != nil {
+		return v.InvalidParam(err.Error())
 	}
-	feedback := fmt.Sprintf("field must be greater than field '%s'", v.Param)
+	feedback := fmt.Sprintf("field must be greater than or equal to field '%s'", v.FieldName(v.Param))
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		field = field.Elem()
 	}
-	target := v.Struct.FieldByName(v.Param)
 	if target.Kind() == reflect.Pointer {
 		if target.IsNil() {
-			return v.Error(feedback)
+			return fieldError(v, feedback)
 		}
 		target = target.Elem()
 	}
@@ -906,7 +864,7 @@ func gtefieldValidator(v *Validation) error {
 	case reflect.Struct:
 		if field.CanConvert(timeType) {
 			if !target.CanConvert(timeType) {
-				return v.ValidatorError(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
+				return v.UnsupportedType(fmt.Sprintf("target field '%s' cannot be compared", target.Type().Name()))
 			}
 			value := field.Interface().(time.Time)
 			targetVal := target.Interface().(time.Time)
@@ -914,44 +872,259 @@ func gtefieldValidator(v *Validation) error {
 				return nil
 			}
 		} else {
-			return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+			return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 		}
 	default:
-		return v.ValidatorError(fmt.Sprintf("not support '%s' type", v.StructField.Type))
+		return v.UnsupportedType(fmt.Sprintf("not support '%s' type", v.StructField.Type))
 	}
-	return v.Error(feedback)
+	return fieldError(v, feedback)
 }
 
 func prefixValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Errorf("field must contain the string prefix '%s'", v.Param)
+			return v.ErrorCode(v.Flag, map[string]any{"prefix": v.Param}, fmt.Sprintf("field must contain the string prefix '%s'", v.Param))
 		}
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if strings.HasPrefix(field.String(), v.Param) {
+	if matchPrefix(v.Param, field.String()) {
 		return nil
 	}
-	return v.Errorf("field must contain the string prefix '%s'", v.Param)
+	return v.ErrorCode(v.Flag, map[string]any{"prefix": v.Param}, fmt.Sprintf("field must contain the string prefix '%s'", v.Param))
 }
 
 func suffixValidator(v *Validation) error {
 	field := v.Field
 	if field.Kind() == reflect.Pointer {
 		if field.IsNil() {
-			return v.Errorf("field must contain the string suffix '%s'", v.Param)
+			return v.ErrorCode(v.Flag, map[string]any{"suffix": v.Param}, fmt.Sprintf("field must contain the string suffix '%s'", v.Param))
 		}
 		field = field.Elem()
 	}
 	if field.Kind() != reflect.String {
-		return v.ValidatorError("validator only support 'string' or '*string' type")
+		return v.UnsupportedType("validator only support 'string' or '*string' type")
 	}
-	if strings.HasPrefix(field.String(), v.Param) {
+	if matchSuffix(v.Param, field.String()) {
 		return nil
 	}
-	return v.Errorf("field must contain the string suffix '%s'", v.Param)
+	return v.ErrorCode(v.Flag, map[string]any{"suffix": v.Param}, fmt.Sprintf("field must contain the string suffix '%s'", v.Param))
+}
+
+// required if all the fields equal the values, param: Field1 value1 Field2 value2
+func requiredIfValidator(v *Validation) error {
+	cond, err := v.conditionParam(true)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if v.Field.IsZero() && cond.matchAll(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field is required when %s", cond.pairs(v.FieldName)))
+	}
+	return nil
+}
+
+// required unless all the fields equal the values, param: Field1 value1 Field2 value2
+func requiredUnlessValidator(v *Validation) error {
+	cond, err := v.conditionParam(true)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if v.Field.IsZero() && !cond.matchAll(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field is required unless %s", cond.pairs(v.FieldName)))
+	}
+	return nil
+}
+
+// required if any of the fields is present, param: Field1 Field2
+func requiredWithValidator(v *Validation) error {
+	cond, err := v.conditionParam(false)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if v.Field.IsZero() && cond.anyPresent(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field is required when %s is present", cond.fieldNames(v.FieldName)))
+	}
+	return nil
+}
+
+// required if any of the fields is not present, param: Field1 Field2
+func requiredWithoutValidator(v *Validation) error {
+	cond, err := v.conditionParam(false)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if v.Field.IsZero() && cond.anyAbsent(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field is required when %s is not present", cond.fieldNames(v.FieldName)))
+	}
+	return nil
+}
+
+// empty if all the fields equal the values, param: Field1 value1 Field2 value2
+func excludedIfValidator(v *Validation) error {
+	cond, err := v.conditionParam(true)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if !v.Field.IsZero() && cond.matchAll(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field must be empty when %s", cond.pairs(v.FieldName)))
+	}
+	return nil
+}
+
+// empty unless all the fields equal the values, param: Field1 value1 Field2 value2
+func excludedUnlessValidator(v *Validation) error {
+	cond, err := v.conditionParam(true)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if !v.Field.IsZero() && !cond.matchAll(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field must be empty unless %s", cond.pairs(v.FieldName)))
+	}
+	return nil
+}
+
+// empty if any of the fields is present, param: Field1 Field2
+func excludedWithValidator(v *Validation) error {
+	cond, err := v.conditionParam(false)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if !v.Field.IsZero() && cond.anyPresent(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field must be empty when %s is present", cond.fieldNames(v.FieldName)))
+	}
+	return nil
+}
+
+// empty if any of the fields is not present, param: Field1 Field2
+func excludedWithoutValidator(v *Validation) error {
+	cond, err := v.conditionParam(false)
+	if err != nil {
+		return v.InvalidParam(err.Error())
+	}
+	if !v.Field.IsZero() && cond.anyAbsent(v.Struct) {
+		return v.ErrorCode(v.Flag, cond.params(v.FieldName), fmt.Sprintf("field must be empty when %s is not present", cond.fieldNames(v.FieldName)))
+	}
+	return nil
+}
+
+// lenError returns the feedback of len, coded len.exact or len.range
+func lenError(v *Validation, args []int, s string) error {
+	if len(args) == 1 {
+		return v.ErrorCode("len.exact", map[string]any{"len": args[0]}, s)
+	}
+	return v.ErrorCode("len.range", map[string]any{"min": args[0], "max": args[1]}, s)
+}
+
+// fieldError returns the feedback of the *_field validators, the target field is kept in Params["field"]
+func fieldError(v *Validation, s string) error {
+	return v.ErrorCode(v.Flag, map[string]any{"field": v.FieldName(v.Param)}, s)
+}
+
+// stringMatchers are the checks of the builtin string validators, shared with the generated code, see MatchString
+var stringMatchers = map[string]func(param string, s string) bool{
+	"email":    func(param string, s string) bool { return matchEmail(s) },
+	"phone":    func(param string, s string) bool { return matchPhone(s) },
+	"ip":       func(param string, s string) bool { return matchIP(s) },
+	"number":   func(param string, s string) bool { return matchNumber(s) },
+	"lower":    func(param string, s string) bool { return matchLower(s) },
+	"upper":    func(param string, s string) bool { return matchUpper(s) },
+	"alpha":    func(param string, s string) bool { return matchAlpha(s) },
+	"username": func(param string, s string) bool { return matchUsername(s) },
+	"password": matchPassword,
+	"prefix":   matchPrefix,
+	"suffix":   matchSuffix,
+}
+
+// Pattern returns a regular expression in the ECMA 262 syntax of JSON Schema, matched by the strings
+// passing the builtin string validator flag, false if the validator isn't a pattern
+func Pattern(flag string, param string) (string, bool) {
+	switch flag {
+	case "email":
+		return emailRegx.String(), true
+	case "phone":
+		trim := func(re *regexp.Regexp) string {
+			return strings.TrimSuffix(strings.TrimPrefix(re.String(), "^"), "$")
+		}
+		return fmt.Sprintf("^(?:(?=\\+86)%s|(?!\\+86)%s)$", trim(chinaPhoneRegx), trim(phoneRegx)), true
+	case "number":
+		return numberRegx.String(), true
+	case "lower":
+		return "^[^A-Z]*$", true
+	case "upper":
+		return "^[^a-z]*$", true
+	case "alpha":
+		return alphaRegex.String(), true
+	case "username":
+		return usernameRegex.String(), true
+	case "password":
+		regexps, ok := passwordRegexps[param]
+		if !ok {
+			return "", false
+		}
+		pattern := "^"
+		for _, re := range regexps {
+			pattern += "(?=.*" + re.String() + ")"
+		}
+		return pattern, true
+	case "prefix":
+		return "^" + regexp.QuoteMeta(param), true
+	case "suffix":
+		return regexp.QuoteMeta(param) + "$", true
+	}
+	return "", false
+}
+
+func matchEmail(s string) bool {
+	return emailRegx.MatchString(s)
+}
+
+func matchPhone(s string) bool {
+	if strings.HasPrefix(s, "+86") {
+		return chinaPhoneRegx.MatchString(s)
+	}
+	return phoneRegx.MatchString(s)
+}
+
+func matchIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func matchNumber(s string) bool {
+	return numberRegx.MatchString(s)
+}
+
+func matchLower(s string) bool {
+	return s == strings.ToLower(s)
+}
+
+func matchUpper(s string) bool {
+	return s == strings.ToUpper(s)
+}
+
+func matchAlpha(s string) bool {
+	return alphaRegex.MatchString(s)
+}
+
+func matchUsername(s string) bool {
+	return usernameRegex.MatchString(s)
+}
+
+func matchPassword(param string, s string) bool {
+	for _, regex := range passwordRegexps[param] {
+		if !regex.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchPrefix(param string, s string) bool {
+	return strings.HasPrefix(s, param)
+}
+
+func matchSuffix(param string, s string) bool {
+	return strings.HasSuffix(s, param)
 }
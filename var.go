@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"reflect"
+)
+
+// otherFieldName is the name of the field holding the other value of VarWithField
+const otherFieldName = "Other"
+
+type varKey struct {
+	typ       reflect.Type
+	structTyp reflect.Type
+	tag       string
+}
+
+type varPlan struct {
+	value *valuePlan
+	err   error
+}
+
+// Var validates a single value against tag, e.g. Var(email, "required,email").
+// It returns a *FieldError with an empty Path when the value is invalid. If the tag
+// dives into the elements of the value, or the value is a struct, a *ValidationError
+// holding a FieldError for each invalid element is returned instead.
+func (self *Engine) Var(value interface{}, tag string) error {
+	return self.validateVar(value, reflect.Value{}, tag)
+}
+
+// VarWithField validates value against tag like Var, the *_field validators of the tag
+// compare value with other, e.g. VarWithField(password2, password, "eq_field").
+// The param of the *_field validators is optional and only used in the feedback, "Other" by default
+func (self *Engine) VarWithField(value interface{}, other interface{}, tag string) error {
+	otherVal := elemValue(reflect.ValueOf(&other).Elem())
+	structTyp := reflect.StructOf([]reflect.StructField{
+		{Name: otherFieldName, Type: otherVal.Type()},
+	})
+	structVal := reflect.New(structTyp).Elem()
+	structVal.Field(0).Set(otherVal)
+	return self.validateVar(value, structVal, tag)
+}
+
+func (self *Engine) validateVar(value interface{}, structVal reflect.Value, tag string) error {
+	field := elemValue(reflect.ValueOf(&value).Elem())
+	key := varKey{
+		typ: field.Type(),
+		tag: tag,
+	}
+	if structVal.IsValid() {
+		key.structTyp = structVal.Type()
+	}
+	vp, err := self.varPlan(key)
+	if err != nil {
+		return err
+	}
+	self.lock.RLock()
+	structError := &ValidationError{
+		Detail: make([]*FieldError, 0),
+		limits: self.limits,
+	}
+	self.lock.RUnlock()
+	fieldTyp := reflect.StructField{Type: field.Type()}
+	if err := self.validateValue(fieldTyp, field, structVal, vp, "", structError); err != nil {
+		return err
+	}
+	if len(structError.Detail) == 0 {
+		return nil
+	}
+	if len(structError.Detail) == 1 && structError.Detail[0].Path == "" {
+		return structError.Detail[0]
+	}
+	return structError
+}
+
+// varPlan returns the cached plan of the tag for the value type, compiling it on first use
+func (self *Engine) varPlan(key varKey) (*valuePlan, error) {
+	self.lock.RLock()
+	plan, ok := self.vars[key]
+	self.lock.RUnlock()
+	if ok {
+		return plan.value, plan.err
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if plan, ok = self.vars[key]; !ok {
+		plan = &varPlan{}
+		var flags []Flag
+		if flags, plan.err = parseFlags(key.tag); plan.err == nil {
+			plan.value, plan.err = self.compileValue("", elemType(key.typ), key.structTyp, flags)
+		}
+		if plan.err == nil && key.structTyp != nil {
+			resolveOtherField(plan.value)
+		}
+		self.vars[key] = plan
+	}
+	return plan.value, plan.err
+}
+
+// resolveOtherField points the *_field validators to the other value of VarWithField
+func resolveOtherField(vp *valuePlan) {
+	if vp == nil {
+		return
+	}
+	resolveOtherRules(vp.rules)
+	resolveOtherField(vp.keys)
+	resolveOtherField(vp.elem)
+}
+
+func resolveOtherRules(rules []*rule) {
+	for _, rule := range rules {
+		if isCrossField(rule.flag) {
+			rule.arg = fieldIndex{0}
+			// the feedback names the other value after its field without a param
+			if rule.param == "" {
+				rule.param = otherFieldName
+			}
+		}
+		resolveOtherRules(rule.alternatives)
+	}
+}